@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// Role grants everything the roles before it grant.
+type Role int
+
+const (
+	None Role = iota
+	// Reader may list, stat and read the tree and follow its changes.
+	Reader
+	// Writer may also change the tree.
+	Writer
+	// Admin may also change quotas and the raft membership.
+	Admin
+)
+
+var roleNames = map[Role]string{
+	None:   "none",
+	Reader: "reader",
+	Writer: "writer",
+	Admin:  "admin",
+}
+
+func ParseRole(s string) (Role, error) {
+	for r, name := range roleNames {
+		if s == name {
+			return r, nil
+		}
+	}
+	return None, fmt.Errorf("unknown role %q", s)
+}
+
+func (r Role) String() string {
+	return roleNames[r]
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Role) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	role, err := ParseRole(s)
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
+
+// Token is a static API token, sent as "Authorization: Bearer <token>".
+type Token struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	Role  Role   `json:"role"`
+}
+
+// Peer grants a role to a libp2p peer calling the gRPC services, the
+// members of the raft cluster are admins.
+type Peer struct {
+	ID   string `json:"id"`
+	Role Role   `json:"role"`
+}
+
+type Config struct {
+	// Enabled turns authentication on, without it every caller is an admin.
+	Enabled bool    `json:"enabled"`
+	Tokens  []Token `json:"tokens" default:"[]"`
+	JWT     JWT     `json:"jwt"`
+	Peers   []Peer  `json:"peers" default:"[]"`
+}
+
+// Identity is who a request was authenticated as.
+type Identity struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+}
+
+func (id Identity) Allows(role Role) bool {
+	return id.Role >= role
+}
+
+type identityKey struct{}
+
+// Key is where the identity is kept in contexts that only look values up by
+// string, such as gin's.
+const Key = "icetrays.identity"
+
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	if id, ok := ctx.Value(identityKey{}).(Identity); ok {
+		return id, true
+	}
+	id, ok := ctx.Value(Key).(Identity)
+	return id, ok
+}
+
+// anonymous is every caller when authentication is disabled, it has no name
+// so that ACLs do not apply to it.
+var anonymous = Identity{Role: Admin}
+
+// Authenticator checks API tokens, JWTs and peer ids against the Config.
+// members returns the ids of the raft cluster members.
+type Authenticator struct {
+	config  Config
+	members func() []string
+}
+
+func New(config Config, members func() []string) *Authenticator {
+	return &Authenticator{config: config, members: members}
+}
+
+func (a *Authenticator) Enabled() bool {
+	return a != nil && a.config.Enabled
+}
+
+// Token authenticates a bearer token, either a static token or a JWT signed
+// with the configured secret.
+func (a *Authenticator) Token(token string) (Identity, error) {
+	if !a.Enabled() {
+		return anonymous, nil
+	}
+	if token == "" {
+		return Identity{}, status.Error(codes.Unauthenticated, "missing token")
+	}
+	for _, t := range a.config.Tokens {
+		if t.Token != "" && subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return Identity{Name: t.Name, Role: t.Role}, nil
+		}
+	}
+	if a.config.JWT.Secret != "" && strings.Count(token, ".") == 2 {
+		id, err := a.config.JWT.verify(token)
+		if err != nil {
+			return Identity{}, status.Errorf(codes.Unauthenticated, "invalid token: %s", err)
+		}
+		return id, nil
+	}
+	return Identity{}, status.Error(codes.Unauthenticated, "invalid token")
+}
+
+// Peer authenticates a libp2p peer, peers neither in the cluster nor in the
+// allowlist are rejected.
+func (a *Authenticator) Peer(id string) (Identity, error) {
+	if !a.Enabled() {
+		return anonymous, nil
+	}
+	for _, m := range a.members() {
+		if m == id {
+			return Identity{Name: id, Role: Admin}, nil
+		}
+	}
+	for _, p := range a.config.Peers {
+		if p.ID == id {
+			return Identity{Name: id, Role: p.Role}, nil
+		}
+	}
+	return Identity{}, status.Errorf(codes.PermissionDenied, "peer %s is not allowed", id)
+}
+
+// Authorize fails with PermissionDenied unless id has role.
+func Authorize(id Identity, role Role) error {
+	if !id.Allows(role) {
+		return status.Errorf(codes.PermissionDenied, "%s is a %s, %s required", id.Name, id.Role, role)
+	}
+	return nil
+}
+
+// methodRoles is the role each gRPC method needs, methods not listed need
+// Admin.
+var methodRoles = map[string]Role{
+	"/pb.RemoteExecute/Execute": Writer,
+	"/pb.Reader/ReadIndex":      Reader,
+	"/pb.ChangeFeed/Subscribe":  Reader,
+	"/pb.PinTracker/Status":     Reader,
+}
+
+func methodRole(method string, req interface{}) Role {
+	role, ok := methodRoles[method]
+	if !ok {
+		return Admin
+	}
+	if ins, ok := req.(*pb.Instruction); ok {
+		return InstructionRole(ins)
+	}
+	return role
+}
+
+// InstructionRole is the role needed to submit ins.
+func InstructionRole(ins *pb.Instruction) Role {
+	switch ins.GetCode() {
+	case pb.Instruction_QUOTA, pb.Instruction_ACL:
+		return Admin
+	case pb.Instruction_TX:
+		role := Writer
+		for _, step := range ins.GetTx().GetInstructions() {
+			if r := InstructionRole(step); r > role {
+				role = r
+			}
+		}
+		return role
+	default:
+		return Writer
+	}
+}
+
+func (a *Authenticator) peer(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unknown peer")
+	}
+	id, err := a.Peer(p.Addr.String())
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(id, methodRole(method, req)); err != nil {
+		return nil, err
+	}
+	// members forward the instructions of the callers of their API, other
+	// peers submit their own
+	if ins, ok := req.(*pb.Instruction); ok && !id.Allows(Admin) {
+		ins.Principal = id.Name
+		if ins.Origin == nil {
+			ins.Origin = &pb.Origin{}
+		}
+		ins.Origin.Client = id.Name
+	}
+	return NewContext(ctx, id), nil
+}
+
+// UnaryInterceptor authorizes the peers calling unary gRPC methods.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !a.Enabled() {
+			return handler(ctx, req)
+		}
+		ctx, err := a.peer(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authorizes the peers calling streaming gRPC methods.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !a.Enabled() {
+			return handler(srv, ss)
+		}
+		if _, err := a.peer(ss.Context(), info.FullMethod, nil); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
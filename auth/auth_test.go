@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func sign(t *testing.T, secret string, claims map[string]interface{}) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	bs, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(bs)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestToken(t *testing.T) {
+	a := New(Config{
+		Enabled: true,
+		Tokens:  []Token{{Name: "ci", Token: "s3cret", Role: Writer}},
+		JWT:     JWT{Secret: "key", Issuer: "icetrays"},
+	}, nil)
+	exp := time.Now().Add(time.Hour).Unix()
+	cases := []struct {
+		token string
+		want  Role
+		code  codes.Code
+	}{
+		{"s3cret", Writer, codes.OK},
+		{"", None, codes.Unauthenticated},
+		{"wrong", None, codes.Unauthenticated},
+		{sign(t, "key", map[string]interface{}{"sub": "ana", "iss": "icetrays", "exp": exp, "role": "reader"}), Reader, codes.OK},
+		{sign(t, "other", map[string]interface{}{"sub": "ana", "iss": "icetrays", "exp": exp, "role": "admin"}), None, codes.Unauthenticated},
+		{sign(t, "key", map[string]interface{}{"sub": "ana", "iss": "icetrays", "exp": 1, "role": "admin"}), None, codes.Unauthenticated},
+		{sign(t, "key", map[string]interface{}{"sub": "ana", "iss": "else", "exp": exp, "role": "admin"}), None, codes.Unauthenticated},
+		{sign(t, "key", map[string]interface{}{"sub": "ana", "iss": "icetrays", "role": "root"}), None, codes.Unauthenticated},
+	}
+	for i, tc := range cases {
+		id, err := a.Token(tc.token)
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("case %d: got %s, want %s", i, code, tc.code)
+			continue
+		}
+		if id.Role != tc.want {
+			t.Errorf("case %d: got role %s, want %s", i, id.Role, tc.want)
+		}
+	}
+
+	id, err := New(Config{}, nil).Token("")
+	if err != nil || id.Role != Admin {
+		t.Fatalf("disabled authentication: got %v %v", id, err)
+	}
+}
+
+func TestPeer(t *testing.T) {
+	a := New(Config{
+		Enabled: true,
+		Peers:   []Peer{{ID: "QmReader", Role: Reader}},
+	}, func() []string { return []string{"QmMember"} })
+	if id, err := a.Peer("QmMember"); err != nil || id.Role != Admin {
+		t.Errorf("member: got %v %v", id, err)
+	}
+	if id, err := a.Peer("QmReader"); err != nil || id.Role != Reader {
+		t.Errorf("allowlisted peer: got %v %v", id, err)
+	}
+	if _, err := a.Peer("QmStranger"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("unknown peer: got %v", err)
+	}
+}
+
+func TestMethodRole(t *testing.T) {
+	quota := &pb.Instruction{Code: pb.Instruction_TX, Tx: &pb.Transaction{Instructions: []*pb.Instruction{
+		{Code: pb.Instruction_MKDIR},
+		{Code: pb.Instruction_QUOTA},
+	}}}
+	cases := []struct {
+		method string
+		req    interface{}
+		want   Role
+	}{
+		{"/pb.RemoteExecute/Execute", &pb.Instruction{Code: pb.Instruction_CP}, Writer},
+		{"/pb.RemoteExecute/Execute", quota, Admin},
+		{"/pb.Reader/ReadIndex", nil, Reader},
+		{"/pb.Membership/AddVoter", nil, Admin},
+	}
+	for _, tc := range cases {
+		if got := methodRole(tc.method, tc.req); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.method, got, tc.want)
+		}
+	}
+}
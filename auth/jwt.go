@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// JWT accepts HS256 tokens signed with Secret. The role claim holds the
+// role name and sub names the caller, Issuer and Audience are checked when
+// set.
+type JWT struct {
+	Secret   string `json:"secret"`
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+}
+
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Role      Role     `json:"role"`
+}
+
+// audience is a single string or a list of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+func (j JWT) verify(token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Identity{}, err
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		return Identity{}, err
+	}
+	if h.Alg != "HS256" {
+		return Identity{}, errors.New("unsupported algorithm " + h.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, err
+	}
+	mac := hmac.New(sha256.New, []byte(j.Secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return Identity{}, errors.New("bad signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Identity{}, err
+	}
+	c := claims{}
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Identity{}, err
+	}
+	now := time.Now().Unix()
+	switch {
+	case c.ExpiresAt != 0 && now >= c.ExpiresAt:
+		return Identity{}, errors.New("expired")
+	case c.NotBefore != 0 && now < c.NotBefore:
+		return Identity{}, errors.New("not valid yet")
+	case j.Issuer != "" && c.Issuer != j.Issuer:
+		return Identity{}, errors.New("wrong issuer")
+	case j.Audience != "" && !contains(c.Audience, j.Audience):
+		return Identity{}, errors.New("wrong audience")
+	case c.Role == None:
+		return Identity{}, errors.New("no role")
+	}
+	return Identity{Name: c.Subject, Role: c.Role}, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,506 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type apiError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+type entry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	Hash string `json:"hash"`
+	attrs
+}
+
+type listing struct {
+	Path    string  `json:"path"`
+	Entries []entry `json:"entries"`
+}
+
+type stat struct {
+	Path           string `json:"path"`
+	Type           string `json:"type"`
+	Cid            string `json:"cid"`
+	Size           uint64 `json:"size"`
+	CumulativeSize uint64 `json:"cumulative_size"`
+	Children       int    `json:"children"`
+	Blocks         int    `json:"blocks"`
+	Target         string `json:"target"`
+	attrs
+}
+
+type attrs struct {
+	Mode     uint32            `json:"mode"`
+	Mtime    *time.Time        `json:"mtime"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type dirUsage struct {
+	Path    string `json:"path"`
+	Bytes   uint64 `json:"bytes"`
+	Entries uint64 `json:"entries"`
+	Quota   *struct {
+		MaxBytes   uint64 `json:"max_bytes"`
+		MaxEntries uint64 `json:"max_entries"`
+	} `json:"quota"`
+}
+
+type acl struct {
+	Principal string   `json:"principal"`
+	Prefix    string   `json:"prefix"`
+	Ops       []string `json:"ops"`
+}
+
+type auditRecord struct {
+	Index     uint64    `json:"index"`
+	Op        string    `json:"op"`
+	Path      string    `json:"path"`
+	Src       string    `json:"src"`
+	Principal string    `json:"principal"`
+	Client    string    `json:"client"`
+	RequestID string    `json:"request_id"`
+	Time      time.Time `json:"time"`
+}
+
+type link struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+}
+
+type tree struct {
+	stat
+	Name    string  `json:"name"`
+	Entries []*tree `json:"entries,omitempty"`
+}
+
+// display is the name of t as listings print it.
+func (t *tree) display() string {
+	if t.Type == "symlink" {
+		return t.Name + " -> " + t.Target
+	}
+	return t.Name
+}
+
+type written struct {
+	Path string `json:"path"`
+	Cid  string `json:"cid"`
+}
+
+type change struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	Src  string `json:"src"`
+}
+
+type event struct {
+	Index   uint64    `json:"index"`
+	Term    uint64    `json:"term"`
+	Pre     string    `json:"pre"`
+	Next    string    `json:"next"`
+	Changes []*change `json:"changes"`
+}
+
+type version struct {
+	Index uint64    `json:"index"`
+	Root  string    `json:"root"`
+	Time  time.Time `json:"time"`
+}
+
+type tag struct {
+	Name    string    `json:"name"`
+	Root    string    `json:"root"`
+	Index   uint64    `json:"index"`
+	Created time.Time `json:"created"`
+}
+
+type peer struct {
+	ID       string `json:"id"`
+	Suffrage string `json:"suffrage"`
+	Leader   bool   `json:"leader"`
+}
+
+type status struct {
+	ID           string `json:"id"`
+	Leader       string `json:"leader"`
+	State        string `json:"state"`
+	Root         string `json:"root"`
+	Index        uint64 `json:"index"`
+	Inconsistent bool   `json:"inconsistent"`
+}
+
+type client struct {
+	base        string
+	consistency string
+	// at reads an earlier version of the tree, see the history command.
+	at string
+	// expectRoot makes mutations fail unless the tree still has this root.
+	expectRoot string
+	// token authenticates the requests when the daemon requires it.
+	token string
+	http  *http.Client
+}
+
+func newClient(base, consistency string) *client {
+	return &client{
+		base:        strings.TrimRight(base, "/"),
+		consistency: consistency,
+		http:        &http.Client{Timeout: time.Minute},
+	}
+}
+
+// useTLS trusts the CAs in caFile besides the system ones, or any
+// certificate with insecure, and presents the client certificate in
+// certFile and keyFile to daemons requiring mutual TLS.
+func (c *client) useTLS(caFile, certFile, keyFile string, insecure bool) error {
+	config := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		bs, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bs) {
+			return fmt.Errorf("no certificate found in %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	c.http.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
+	return nil
+}
+
+func (c *client) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(bs)
+	}
+	req, err := http.NewRequest(method, c.base+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(req, out)
+}
+
+func (c *client) send(req *http.Request, out interface{}) error {
+	res, err := c.open(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// open sends req and returns the response if the daemon accepted it.
+func (c *client) open(req *http.Request) (*http.Response, error) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach daemon at %s: %w", c.base, err)
+	}
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+		e := &apiError{Status: res.StatusCode}
+		_ = json.NewDecoder(res.Body).Decode(e)
+		return nil, e
+	}
+	return res, nil
+}
+
+func (c *client) ls(path string) (*listing, error) {
+	l := &listing{}
+	return l, c.do("GET", "/v1/ls"+escapePath(path)+c.query(nil), nil, l)
+}
+
+func (c *client) stat(path string) (*stat, error) {
+	st := &stat{}
+	return st, c.do("GET", "/v1/stat"+escapePath(path)+c.query(nil), nil, st)
+}
+
+func (c *client) tree(path string, depth int) (*tree, error) {
+	t := &tree{}
+	q := url.Values{"depth": {strconv.Itoa(depth)}}
+	return t, c.do("GET", "/v1/tree"+escapePath(path)+c.query(q), nil, t)
+}
+
+// cat streams length bytes of path from offset, a negative length reads to
+// the end of the file.
+func (c *client) cat(path string, offset, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	}
+	req, err := http.NewRequest("GET", c.base+"/v1/cat"+escapePath(path)+c.query(nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	if length >= 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	res, err := c.open(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (c *client) cp(src, dst string, parents bool) error {
+	return c.do("POST", "/v1/cp", c.mutation(map[string]interface{}{"src": src, "dst": dst, "parents": parents}), nil)
+}
+
+func (c *client) put(r io.Reader, path string) (*written, error) {
+	req, err := http.NewRequest("PUT", c.base+"/v1/files"+escapePath(path)+c.expect(nil), r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	w := &written{}
+	return w, c.send(req, w)
+}
+
+func (c *client) mv(src, dst string) error {
+	return c.do("POST", "/v1/mv", c.mutation(map[string]interface{}{"src": src, "dst": dst}), nil)
+}
+
+func (c *client) rm(path string, recursive, force bool) error {
+	q := url.Values{}
+	if recursive {
+		q.Set("recursive", "true")
+	}
+	if force {
+		q.Set("force", "true")
+	}
+	return c.do("DELETE", "/v1/files"+escapePath(path)+c.expect(q), nil, nil)
+}
+
+func (c *client) mkdir(path string) error {
+	return c.do("POST", "/v1/mkdir", c.mutation(map[string]interface{}{"path": path}), nil)
+}
+
+// watch calls fn with every event of the change feed starting at from. It
+// returns when the daemon ends the stream or fn fails.
+func (c *client) watch(from uint64, fn func(raw []byte, e *event) error) error {
+	path := "/v1/events"
+	if from > 0 {
+		path += "?from=" + strconv.FormatUint(from, 10)
+	}
+	req, err := http.NewRequest("GET", c.base+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	// the feed never ends on its own, so it cannot share the request timeout
+	stream := &client{base: c.base, token: c.token, http: &http.Client{Transport: c.http.Transport}}
+	res, err := stream.open(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		raw := []byte(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		e := &event{}
+		if err := json.Unmarshal(raw, e); err != nil {
+			return err
+		}
+		if err := fn(raw, e); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *client) restore(path, at string) error {
+	return c.do("POST", "/v1/restore", c.mutation(map[string]interface{}{"path": path, "at": at}), nil)
+}
+
+func (c *client) history(limit int) ([]version, error) {
+	var versions []version
+	return versions, c.do("GET", "/v1/history?limit="+strconv.Itoa(limit), nil, &versions)
+}
+
+func (c *client) symlink(target, path string) error {
+	return c.do("POST", "/v1/symlink", c.mutation(map[string]interface{}{"path": path, "target": target}), nil)
+}
+
+func (c *client) setattr(path, mode, mtime string, metadata map[string]string) error {
+	body := map[string]interface{}{"path": path}
+	if mode != "" {
+		body["mode"] = mode
+	}
+	if mtime != "" {
+		body["mtime"] = mtime
+	}
+	if len(metadata) > 0 {
+		body["metadata"] = metadata
+	}
+	return c.do("POST", "/v1/setattr", c.mutation(body), nil)
+}
+
+func (c *client) quota(path string, maxBytes, maxEntries uint64) error {
+	return c.do("POST", "/v1/quota", c.mutation(map[string]interface{}{
+		"path":        path,
+		"max_bytes":   maxBytes,
+		"max_entries": maxEntries,
+	}), nil)
+}
+
+func (c *client) usage(path string) (*dirUsage, error) {
+	u := &dirUsage{}
+	return u, c.do("GET", "/v1/usage"+escapePath(path)+c.query(nil), nil, u)
+}
+
+func (c *client) quotas() ([]*dirUsage, error) {
+	var usages []*dirUsage
+	return usages, c.do("GET", "/v1/quotas"+c.query(nil), nil, &usages)
+}
+
+func (c *client) acl(principal, prefix string, ops []string) error {
+	return c.do("POST", "/v1/acl", c.mutation(map[string]interface{}{
+		"principal": principal,
+		"prefix":    prefix,
+		"ops":       ops,
+	}), nil)
+}
+
+func (c *client) acls() ([]acl, error) {
+	var acls []acl
+	return acls, c.do("GET", "/v1/acls"+c.query(nil), nil, &acls)
+}
+
+func (c *client) audit(path, since string, limit int) ([]auditRecord, error) {
+	q := url.Values{}
+	q.Set("path", path)
+	q.Set("limit", strconv.Itoa(limit))
+	if since != "" {
+		q.Set("since", since)
+	}
+	var records []auditRecord
+	return records, c.do("GET", "/v1/audit?"+q.Encode(), nil, &records)
+}
+
+func (c *client) readlink(path string) (*link, error) {
+	l := &link{}
+	return l, c.do("GET", "/v1/readlink"+escapePath(path)+c.query(nil), nil, l)
+}
+
+func (c *client) tag(name string) error {
+	return c.do("POST", "/v1/tags", map[string]string{"name": name}, nil)
+}
+
+func (c *client) untag(name string) error {
+	return c.do("DELETE", "/v1/tags/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *client) tags() ([]tag, error) {
+	var tags []tag
+	return tags, c.do("GET", "/v1/tags"+c.query(nil), nil, &tags)
+}
+
+func (c *client) status() (*status, error) {
+	s := &status{}
+	return s, c.do("GET", "/v1/status", nil, s)
+}
+
+func (c *client) peers() ([]peer, error) {
+	var peers []peer
+	return peers, c.do("GET", "/v1/peers", nil, &peers)
+}
+
+// query encodes q along with the read consistency of the client.
+func (c *client) query(q url.Values) string {
+	if q == nil {
+		q = url.Values{}
+	}
+	if c.consistency != "" {
+		q.Set("consistency", c.consistency)
+	}
+	if c.at != "" {
+		q.Set("at", c.at)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// mutation adds the expected root of the client to a request body.
+func (c *client) mutation(body map[string]interface{}) map[string]interface{} {
+	if c.expectRoot != "" {
+		body["expect_root"] = c.expectRoot
+	}
+	return body
+}
+
+// expect is mutation for requests without a body, it encodes q along with
+// the expected root.
+func (c *client) expect(q url.Values) string {
+	if q == nil {
+		q = url.Values{}
+	}
+	if c.expectRoot != "" {
+		q.Set("expect_root", c.expectRoot)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+func escapePath(p string) string {
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Path: p}).EscapedPath()
+}
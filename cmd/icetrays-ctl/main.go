@@ -0,0 +1,477 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `usage: icetrays-ctl [--api URL] [--token TOKEN] [--ca FILE] [--cert FILE --key FILE]
+                    [--insecure] [--json] [--consistency LEVEL] [--at VERSION]
+                    [--expect-root CID] <command> [args]
+
+--token, or $ICETRAYS_TOKEN, is the API token or JWT sent to daemons that
+require authentication. For an https:// API, --ca trusts the certificate of
+a daemon that generated its own, and --cert and --key present a client
+certificate to daemons requiring mutual TLS.
+
+ls, stat, cat and ls -r read with the given consistency: stale (default),
+leader or linearizable, or at an earlier version given by --at as a tag, a
+raft index, an RFC 3339 time or a root CID. With --expect-root, cp, put, mv, rm
+and mkdir fail with Aborted if the root of the tree is no longer CID. The
+source of cp and mv and the path of rm may be glob patterns, quoted to keep
+the shell from expanding them, cp and mv then put every match into dst.
+
+commands:
+  ls [-r] <path>     list a directory, -r walks it recursively
+  stat <path>        show size, CID and block count of path
+  cat [-offset N] [-length N] <path>
+                     print the content of a file
+  cp [-p] <src> <dst>
+                     copy a tree path or a CID to dst, -p creates the
+                     parents of dst
+  put <file> <dst>   upload a local file, - reads stdin, and copy it to dst
+  mv <src> <dst>     move src to dst
+  rm [-r] [-f] <path>
+                     remove path, -r removes non empty directories and -f
+                     ignores missing paths
+  mkdir <path>       create a directory and its parents
+  symlink <target> <path>
+                     create a symlink at path, ls, stat and cat follow it
+  readlink <path>    print the target of a symlink
+  quota [-bytes N] [-entries N] <path>
+                     limit the size and number of entries of a directory,
+                     without limits the quota is removed
+  usage <path>       show the size and entries of a directory
+  quotas             show the usage of every directory with a quota
+  acl [-ops OPS] <principal> <prefix>
+                     allow principal the comma separated OPS, such as
+                     cp,mv,rm or *, under prefix, without -ops the entry
+                     is removed
+  acls               list the ACLs
+  audit [-since S] [-n N] <path>
+                     list who changed path or below it, / for the whole
+                     tree, from a raft index or RFC 3339 time given by
+                     -since, or the latest N changes
+  setattr [-mode MODE] [-mtime TIME] [-meta KEY=VALUE]... <path>
+                     set the octal mode, RFC 3339 mtime or user metadata of
+                     path, an empty VALUE removes KEY
+  watch [-from N]    follow the changes made to the tree, -from replays
+                     them from a raft index
+  history [-n N]     list the latest recorded versions of the tree
+  restore <path> <version>
+                     put path back as it was at a version from history
+  tag <name>         name the current root, it stays pinned and in history
+  untag <name>       delete a tag
+  tags               list the tags
+  status             show the state of the node
+  peers              list the raft peers
+  leader             print the current leader
+`
+
+type command struct {
+	args int
+	run  func(c *client, fs *flag.FlagSet) (interface{}, error)
+}
+
+var (
+	asJSON     bool
+	recursive  bool
+	force      bool
+	parents    bool
+	offset     int64
+	length     int64
+	from       uint64
+	limit      int
+	maxBytes   uint64
+	maxEntries uint64
+	mode       string
+	mtime      string
+	metadata   = metaFlag{}
+	ops        string
+	since      string
+)
+
+// metaFlag collects repeated -meta KEY=VALUE flags.
+type metaFlag map[string]string
+
+func (m metaFlag) String() string {
+	return fmt.Sprint(map[string]string(m))
+}
+
+func (m metaFlag) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("%q is not KEY=VALUE", s)
+	}
+	m[kv[0]] = kv[1]
+	return nil
+}
+
+// streamed is returned by commands that printed their output as it arrived.
+type streamed struct{}
+
+var commands = map[string]command{
+	"ls": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		if recursive {
+			return c.tree(fs.Arg(0), -1)
+		}
+		return c.ls(fs.Arg(0))
+	}},
+	"stat": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.stat(fs.Arg(0))
+	}},
+	"cp": {2, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.cp(fs.Arg(0), fs.Arg(1), parents)
+	}},
+	"cat": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.cat(fs.Arg(0), offset, length)
+	}},
+	"put": {2, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		f := os.Stdin
+		if name := fs.Arg(0); name != "-" {
+			var err error
+			if f, err = os.Open(name); err != nil {
+				return nil, err
+			}
+			defer f.Close()
+		}
+		return c.put(f, fs.Arg(1))
+	}},
+	"mv": {2, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.mv(fs.Arg(0), fs.Arg(1))
+	}},
+	"rm": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.rm(fs.Arg(0), recursive, force)
+	}},
+	"mkdir": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.mkdir(fs.Arg(0))
+	}},
+	"symlink": {2, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.symlink(fs.Arg(0), fs.Arg(1))
+	}},
+	"setattr": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.setattr(fs.Arg(0), mode, mtime, metadata)
+	}},
+	"quota": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.quota(fs.Arg(0), maxBytes, maxEntries)
+	}},
+	"usage": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.usage(fs.Arg(0))
+	}},
+	"quotas": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.quotas()
+	}},
+	"acl": {2, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		var list []string
+		if ops != "" {
+			list = strings.Split(ops, ",")
+		}
+		return nil, c.acl(fs.Arg(0), fs.Arg(1), list)
+	}},
+	"acls": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.acls()
+	}},
+	"audit": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.audit(fs.Arg(0), since, limit)
+	}},
+	"readlink": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.readlink(fs.Arg(0))
+	}},
+	"watch": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return streamed{}, c.watch(from, func(raw []byte, e *event) error {
+			if asJSON {
+				_, err := fmt.Printf("%s\n", raw)
+				return err
+			}
+			for _, ch := range e.Changes {
+				if _, err := fmt.Printf("%d\t%s\t%s\t%s\n", e.Index, ch.Op, ch.Path, ch.Src); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}},
+	"history": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.history(limit)
+	}},
+	"restore": {2, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.restore(fs.Arg(0), fs.Arg(1))
+	}},
+	"tag": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.tag(fs.Arg(0))
+	}},
+	"untag": {1, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return nil, c.untag(fs.Arg(0))
+	}},
+	"tags": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.tags()
+	}},
+	"status": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.status()
+	}},
+	"peers": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		return c.peers()
+	}},
+	"leader": {0, func(c *client, fs *flag.FlagSet) (interface{}, error) {
+		s, err := c.status()
+		if err != nil {
+			return nil, err
+		}
+		if s.Leader == "" {
+			return nil, errors.New("no leader elected")
+		}
+		return s.Leader, nil
+	}},
+}
+
+func main() {
+	api := os.Getenv("ICETRAYS_API")
+	if api == "" {
+		api = "http://127.0.0.1:10086"
+	}
+	flag.StringVar(&api, "api", api, "daemon HTTP API address, defaults to $ICETRAYS_API")
+	token := flag.String("token", os.Getenv("ICETRAYS_TOKEN"), "API token or JWT, defaults to $ICETRAYS_TOKEN")
+	ca := flag.String("ca", os.Getenv("ICETRAYS_CA"), "PEM file of the CAs to trust, defaults to $ICETRAYS_CA")
+	cert := flag.String("cert", os.Getenv("ICETRAYS_CERT"), "PEM client certificate for mutual TLS, defaults to $ICETRAYS_CERT")
+	key := flag.String("key", os.Getenv("ICETRAYS_KEY"), "PEM key of the client certificate, defaults to $ICETRAYS_KEY")
+	insecure := flag.Bool("insecure", false, "do not verify the certificate of the daemon")
+	flag.BoolVar(&asJSON, "json", false, "print raw JSON responses")
+	consistency := flag.String("consistency", "", "read consistency: stale, leader or linearizable")
+	at := flag.String("at", "", "read the version at a tag, raft index, RFC 3339 time or root CID")
+	expectRoot := flag.String("expect-root", "", "only mutate the tree if its root is still this CID")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	name := flag.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.BoolVar(&asJSON, "json", asJSON, "print raw JSON responses")
+	if name == "ls" {
+		fs.BoolVar(&recursive, "r", false, "list recursively")
+	}
+	if name == "rm" {
+		fs.BoolVar(&recursive, "r", false, "remove non empty directories")
+		fs.BoolVar(&force, "f", false, "ignore paths that do not exist")
+	}
+	if name == "quota" {
+		fs.Uint64Var(&maxBytes, "bytes", 0, "largest cumulative size in bytes, 0 is unlimited")
+		fs.Uint64Var(&maxEntries, "entries", 0, "most entries below the directory, 0 is unlimited")
+	}
+	if name == "acl" {
+		fs.StringVar(&ops, "ops", "", "comma separated ops to allow, * allows them all")
+	}
+	if name == "setattr" {
+		fs.StringVar(&mode, "mode", "", "octal permission bits")
+		fs.StringVar(&mtime, "mtime", "", "modification time in RFC 3339")
+		fs.Var(metadata, "meta", "user metadata KEY=VALUE, may be repeated")
+	}
+	if name == "cp" {
+		fs.BoolVar(&parents, "p", false, "create the parent directories of dst")
+	}
+	if name == "watch" {
+		fs.Uint64Var(&from, "from", 0, "raft index to replay the changes from")
+	}
+	if name == "history" {
+		fs.IntVar(&limit, "n", 20, "number of versions to list")
+	}
+	if name == "audit" {
+		fs.StringVar(&since, "since", "", "raft index or RFC 3339 time to list the changes from")
+		fs.IntVar(&limit, "n", 100, "number of changes to list")
+	}
+	if name == "cat" {
+		fs.Int64Var(&offset, "offset", 0, "first byte to print")
+		fs.Int64Var(&length, "length", -1, "number of bytes to print, -1 prints to the end")
+	}
+	_ = fs.Parse(flag.Args()[1:])
+	if fs.NArg() != cmd.args {
+		fmt.Fprintf(os.Stderr, "icetrays-ctl %s: expected %d arguments, got %d\n", name, cmd.args, fs.NArg())
+		os.Exit(2)
+	}
+
+	c := newClient(api, *consistency)
+	c.at = *at
+	c.expectRoot = *expectRoot
+	c.token = *token
+	if *ca != "" || *cert != "" || *insecure {
+		if err := c.useTLS(*ca, *cert, *key, *insecure); err != nil {
+			fmt.Fprintf(os.Stderr, "icetrays-ctl: %s\n", err)
+			os.Exit(2)
+		}
+	}
+	res, err := cmd.run(c, fs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "icetrays-ctl %s: %s\n", name, err)
+		os.Exit(1)
+	}
+	if err := output(res); err != nil {
+		fmt.Fprintf(os.Stderr, "icetrays-ctl %s: %s\n", name, err)
+		os.Exit(1)
+	}
+}
+
+func output(res interface{}) error {
+	if _, ok := res.(streamed); ok {
+		return nil
+	}
+	if r, ok := res.(io.ReadCloser); ok {
+		defer r.Close()
+		_, err := io.Copy(os.Stdout, r)
+		return err
+	}
+	if asJSON {
+		if res == nil {
+			res = map[string]string{"result": "success"}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+	switch res := res.(type) {
+	case *listing:
+		printListing(w, res)
+	case *tree:
+		printTree(w, res, true)
+	case *stat:
+		fmt.Fprintf(w, "path:\t%s\n", res.Path)
+		fmt.Fprintf(w, "type:\t%s\n", res.Type)
+		fmt.Fprintf(w, "cid:\t%s\n", res.Cid)
+		fmt.Fprintf(w, "size:\t%d\n", res.Size)
+		fmt.Fprintf(w, "cumulative size:\t%d\n", res.CumulativeSize)
+		fmt.Fprintf(w, "children:\t%d\n", res.Children)
+		fmt.Fprintf(w, "blocks:\t%d\n", res.Blocks)
+		if res.Mode != 0 {
+			fmt.Fprintf(w, "mode:\t%04o\n", res.Mode)
+		}
+		if res.Mtime != nil {
+			fmt.Fprintf(w, "mtime:\t%s\n", res.Mtime.Format(time.RFC3339))
+		}
+		keys := make([]string, 0, len(res.Metadata))
+		for k := range res.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "meta %s:\t%s\n", k, res.Metadata[k])
+		}
+	case *link:
+		fmt.Fprintln(w, res.Target)
+	case *written:
+		fmt.Fprintf(w, "%s\t%s\n", res.Cid, res.Path)
+	case *status:
+		fmt.Fprintf(w, "id:\t%s\n", res.ID)
+		fmt.Fprintf(w, "state:\t%s\n", res.State)
+		fmt.Fprintf(w, "leader:\t%s\n", res.Leader)
+		fmt.Fprintf(w, "root:\t%s\n", res.Root)
+		fmt.Fprintf(w, "index:\t%d\n", res.Index)
+		fmt.Fprintf(w, "inconsistent:\t%t\n", res.Inconsistent)
+	case []version:
+		for _, v := range res {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", v.Index, v.Time.Format(time.RFC3339), v.Root)
+		}
+	case []tag:
+		for _, t := range res {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", t.Name, t.Index, t.Created.Format(time.RFC3339), t.Root)
+		}
+	case *dirUsage:
+		printUsage(w, res)
+	case []*dirUsage:
+		for _, u := range res {
+			printUsage(w, u)
+		}
+	case []auditRecord:
+		for _, r := range res {
+			path := r.Path
+			if r.Src != "" {
+				path = r.Src + " -> " + r.Path
+			}
+			who := r.Principal
+			if who == "" {
+				who = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", r.Index, r.Time.Format(time.RFC3339), who, r.Client, r.Op, path)
+		}
+	case []acl:
+		for _, a := range res {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", a.Principal, a.Prefix, strings.Join(a.Ops, ","))
+		}
+	case []peer:
+		for _, p := range res {
+			leader := ""
+			if p.Leader {
+				leader = "leader"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", p.ID, p.Suffrage, leader)
+		}
+	case string:
+		fmt.Fprintln(w, res)
+	}
+	return nil
+}
+
+// printUsage prints the path, bytes and entries of u followed by its limits.
+func printUsage(w *tabwriter.Writer, u *dirUsage) {
+	limits := "-\t-"
+	if u.Quota != nil {
+		limits = fmt.Sprintf("%s\t%s", quotaLimit(u.Quota.MaxBytes), quotaLimit(u.Quota.MaxEntries))
+	}
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", u.Path, u.Bytes, u.Entries, limits)
+}
+
+func quotaLimit(n uint64) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+func printListing(w *tabwriter.Writer, l *listing) {
+	for _, e := range l.Entries {
+		printEntry(w, e.Type, e.Size, e.Hash, e.Name)
+	}
+}
+
+// printTree prints every directory of t like ls -R does.
+func printTree(w *tabwriter.Writer, t *tree, first bool) {
+	if t.Type != "directory" {
+		printEntry(w, t.Type, int64(t.Size), t.Cid, t.display())
+		return
+	}
+	if !first {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%s:\n", t.Path)
+	for _, e := range t.Entries {
+		printEntry(w, e.Type, int64(e.Size), e.Cid, e.display())
+	}
+	for _, e := range t.Entries {
+		if e.Type == "directory" {
+			printTree(w, e, false)
+		}
+	}
+}
+
+func printEntry(w *tabwriter.Writer, typ string, size int64, hash, name string) {
+	kind := "-"
+	switch typ {
+	case "directory":
+		kind = "d"
+	case "symlink":
+		kind = "l"
+	}
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", kind, size, hash, name)
+}
@@ -40,17 +40,27 @@ func main() {
 		fx.Provide(modules.InitConfig),
 		fx.Provide(modules.NetConfig),
 		fx.Provide(modules.Network),
+		fx.Provide(modules.GrpcServer),
 		fx.Provide(modules.RaftConfig),
 		fx.Provide(modules.DataStore),
 		fx.Provide(modules.SnapshotStore),
 		fx.Provide(modules.Fsm),
 		fx.Provide(modules.IpfsClient),
 		fx.Provide(modules.Transport),
+		fx.Provide(modules.LogStore),
 		fx.Provide(modules.Raft),
+		fx.Provide(modules.Authenticator),
 		//fx.Provide(modules.RpcClients),
 		fx.StopTimeout(time.Minute),
 		fx.Provide(modules.Node),
+		fx.Provide(modules.Pinner),
+		fx.Provide(modules.PinTracker),
+		fx.Provide(modules.Feed),
+		fx.Provide(modules.History),
+		fx.Provide(modules.Audit),
 		fx.Invoke(modules.Server2),
+		fx.Invoke(modules.Webhooks),
+		fx.Invoke(modules.Metrics),
 		fx.Invoke(T),
 	}
 	app := New(options...)
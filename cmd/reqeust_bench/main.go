@@ -21,15 +21,14 @@ func main() {
 		go func() {
 			defer group.Done()
 			params := struct {
-				Op     string   `json:"op"`
-				Params []string `json:"params"`
+				Src string `json:"src"`
+				Dst string `json:"dst"`
 			}{}
-			params.Op = "cp"
-			s := randomString(5)
-			params.Params = []string{s, "QmayKQWJgWmr46DqWseADyUanmqxh662hPNdAkdHRjiQQH"}
+			params.Src = "QmayKQWJgWmr46DqWseADyUanmqxh662hPNdAkdHRjiQQH"
+			params.Dst = "/" + randomString(5)
 			bs, _ := json.Marshal(params)
 
-			req, err := http.NewRequest("POST", "http://127.0.0.1:10087/fs", bytes.NewReader(bs))
+			req, err := http.NewRequest("POST", "http://127.0.0.1:10087/v1/cp", bytes.NewReader(bs))
 			if err != nil {
 				fmt.Println(err.Error())
 				return
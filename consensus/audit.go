@@ -0,0 +1,67 @@
+package consensus
+
+import (
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/datastore"
+	"time"
+)
+
+// Audit records who made every change applied by the Fsm, each node keeps
+// its own index of the entries it applies.
+type Audit struct {
+	db *datastore.AuditDB
+}
+
+func NewAudit(fsm *Fsm, db *datastore.AuditDB) *Audit {
+	a := &Audit{db: db}
+	fsm.OnApplied(a.record)
+	return a
+}
+
+func (a *Audit) record(applied Applied) {
+	if applied.Restored {
+		return
+	}
+	records := auditRecords(applied.Instructions, nil)
+	if len(records) == 0 {
+		return
+	}
+	if err := a.db.Add(applied.Index, records); err != nil {
+		logger.Errorf("audit entry %d: %s", applied.Index, err)
+	}
+}
+
+func (a *Audit) Query(q datastore.AuditQuery) ([]datastore.AuditRecord, error) {
+	return a.db.Query(q)
+}
+
+// auditRecords flattens inss like the feed does, the steps of a transaction
+// are attributed to the transaction unless they carry their own origin.
+func auditRecords(inss []*pb.Instruction, tx *pb.Instruction) []datastore.AuditRecord {
+	var records []datastore.AuditRecord
+	for _, ins := range inss {
+		by := ins
+		if by.GetOrigin() == nil && tx != nil {
+			by = tx
+		}
+		if ins.GetCode() == pb.Instruction_TX {
+			records = append(records, auditRecords(ins.GetTx().GetInstructions(), by)...)
+			continue
+		}
+		for _, c := range changes([]*pb.Instruction{ins}) {
+			r := datastore.AuditRecord{
+				Op:        c.Op,
+				Path:      c.Path,
+				Src:       c.Src,
+				Principal: by.GetPrincipal(),
+				Client:    by.GetOrigin().GetClient(),
+				RequestID: by.GetOrigin().GetRequestId(),
+			}
+			if t := by.GetOrigin().GetTime(); t != 0 {
+				r.Time = time.Unix(0, t).UTC()
+			}
+			records = append(records, r)
+		}
+	}
+	return records
+}
@@ -0,0 +1,34 @@
+package consensus
+
+import (
+	"github.com/icetrays/icetrays/consensus/pb"
+	"testing"
+	"time"
+)
+
+func TestAuditRecords(t *testing.T) {
+	now := time.Now().UTC()
+	origin := &pb.Origin{Client: "10.0.0.1", RequestId: "r1", Time: now.UnixNano()}
+	inss := []*pb.Instruction{
+		{Code: pb.Instruction_TX, Principal: "ana", Origin: origin, Tx: &pb.Transaction{Instructions: []*pb.Instruction{
+			{Code: pb.Instruction_MKDIR, Params: []string{"/a"}},
+			{Code: pb.Instruction_MV, Params: []string{"/b", "/a/b"}},
+		}}},
+		{Code: pb.Instruction_RM, Params: []string{"/c"}},
+	}
+	records := auditRecords(inss, nil)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	for _, r := range records[:2] {
+		if r.Principal != "ana" || r.Client != "10.0.0.1" || r.RequestID != "r1" || !r.Time.Equal(now) {
+			t.Errorf("transaction step not attributed: %+v", r)
+		}
+	}
+	if r := records[1]; r.Op != "mv" || r.Src != "/b" || r.Path != "/a/b" {
+		t.Errorf("got %+v", r)
+	}
+	if r := records[2]; r.Principal != "" || !r.Time.IsZero() {
+		t.Errorf("unattributed rm got %+v", r)
+	}
+}
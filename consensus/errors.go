@@ -0,0 +1,60 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/consensus/state"
+	"github.com/ipfs/go-mfs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+)
+
+// ErrorCode classifies err as a grpc code, whether it was raised locally or
+// returned by the leader through a RemoteOperator.
+func ErrorCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	switch {
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, ErrVersionNotFound):
+		return codes.NotFound
+	case errors.Is(err, os.ErrExist), errors.Is(err, mfs.ErrDirExists):
+		return codes.AlreadyExists
+	case errors.Is(err, state.ErrParamsNum), errors.Is(err, state.ErrInvalidPath), errors.Is(err, ErrNoOperator),
+		errors.Is(err, ErrInvalidPeer), errors.Is(err, ErrInvalidConsistency), errors.Is(err, state.ErrNotFile),
+		errors.Is(err, state.ErrNestedTransaction), errors.Is(err, ErrInvalidVersion), errors.Is(err, state.ErrInvalidTag),
+		errors.Is(err, state.ErrNotSymlink), errors.Is(err, state.ErrSymlinkLoop), errors.Is(err, state.ErrInvalidAttr),
+		errors.Is(err, state.ErrInvalidACL):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrInconsistent), errors.Is(err, ErrShutdown), errors.Is(err, raft.ErrNotLeader),
+		errors.Is(err, raft.ErrLeadershipLost), errors.Is(err, raft.ErrEnqueueTimeout), errors.Is(err, raft.ErrRaftShutdown),
+		errors.Is(err, ErrFeedClosed):
+		return codes.Unavailable
+	case errors.Is(err, state.ErrPreconditionFailed), errors.Is(err, state.ErrNotEmpty):
+		return codes.FailedPrecondition
+	case errors.Is(err, state.ErrConflict):
+		return codes.Aborted
+	case errors.Is(err, state.ErrOutOfRange), errors.Is(err, ErrCompacted):
+		return codes.OutOfRange
+	case errors.Is(err, state.ErrQuotaExceeded):
+		return codes.ResourceExhausted
+	case errors.Is(err, state.ErrAccessDenied):
+		return codes.PermissionDenied
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
+
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return status.Error(ErrorCode(err), err.Error())
+}
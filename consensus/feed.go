@@ -0,0 +1,225 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"strings"
+	"sync"
+)
+
+// ErrCompacted is returned when a subscriber asks to resume from an index
+// that has already been removed from the raft log by a snapshot.
+var ErrCompacted = errors.New("log compacted")
+
+// ErrFeedClosed ends a subscription that fell behind or whose replay
+// failed, it can be resumed from the index of the last event received.
+var ErrFeedClosed = errors.New("subscription closed")
+
+const feedBuffer = 256
+
+// Feed publishes an Event for every entry applied by the Fsm. Subscribers
+// that fall more than feedBuffer events behind are dropped, they can
+// resume from the index of the last event they received.
+type Feed struct {
+	fsm  *Fsm
+	logs raft.LogStore
+	mtx  sync.Mutex
+	subs map[chan *pb.Event]struct{}
+}
+
+func NewFeed(fsm *Fsm, logs raft.LogStore) *Feed {
+	f := &Feed{
+		fsm:  fsm,
+		logs: logs,
+		subs: make(map[chan *pb.Event]struct{}),
+	}
+	fsm.OnApplied(f.publish)
+	return f
+}
+
+// NewEvent describes an applied entry the way the feed publishes it.
+func NewEvent(applied Applied) *pb.Event {
+	e := &pb.Event{
+		Index: applied.Index,
+		Term:  applied.Term,
+		Pre:   applied.Pre,
+		Next:  applied.Next,
+	}
+	if applied.Restored {
+		e.Changes = []*pb.Change{{Op: "snapshot", Path: "/"}}
+	} else {
+		e.Changes = changes(applied.Instructions)
+	}
+	return e
+}
+
+func (f *Feed) publish(applied Applied) {
+	e := NewEvent(applied)
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe streams events until ctx is done or the subscriber falls behind,
+// the channel is closed in both cases. A non zero from replays the entries
+// still in the raft log starting at that index first.
+func (f *Feed) Subscribe(ctx context.Context, from uint64) (<-chan *pb.Event, error) {
+	if from > 0 {
+		first, err := f.logs.FirstIndex()
+		if err != nil {
+			return nil, err
+		}
+		if from < first {
+			return nil, fmt.Errorf("%w: index %d, first available %d", ErrCompacted, from, first)
+		}
+	}
+	live := make(chan *pb.Event, feedBuffer)
+	f.mtx.Lock()
+	f.subs[live] = struct{}{}
+	f.mtx.Unlock()
+	applied := f.fsm.State.Index()
+
+	out := make(chan *pb.Event)
+	go func() {
+		defer close(out)
+		defer f.unsubscribe(live)
+		send := func(e *pb.Event) bool {
+			select {
+			case out <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for i := from; from > 0 && i <= applied; i++ {
+			e, err := f.replay(i)
+			if err != nil {
+				logger.Warnf("feed replay %d: %s", i, err)
+				return
+			}
+			if e != nil && !send(e) {
+				return
+			}
+		}
+		for {
+			select {
+			case e, ok := <-live:
+				if !ok {
+					return
+				}
+				if e.Index <= applied && !snapshot(e) {
+					continue
+				}
+				if !send(e) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (f *Feed) unsubscribe(ch chan *pb.Event) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if _, ok := f.subs[ch]; ok {
+		delete(f.subs, ch)
+		close(ch)
+	}
+}
+
+// replay rebuilds the event of the log entry at index, entries that do not
+// change the tree yield nil.
+func (f *Feed) replay(index uint64) (*pb.Event, error) {
+	l := &raft.Log{}
+	if err := f.logs.GetLog(index, l); err != nil {
+		if err == raft.ErrLogNotFound {
+			return nil, fmt.Errorf("%w: index %d", ErrCompacted, index)
+		}
+		return nil, err
+	}
+	if l.Type != raft.LogCommand {
+		return nil, nil
+	}
+	inss := &pb.Instructions{}
+	if err := proto.Unmarshal(l.Data, inss); err != nil {
+		return nil, err
+	}
+	return &pb.Event{
+		Index:   l.Index,
+		Term:    l.Term,
+		Pre:     inss.GetCtx().GetPre(),
+		Next:    inss.GetCtx().GetNext(),
+		Changes: changes(inss.Instruction),
+	}, nil
+}
+
+func snapshot(e *pb.Event) bool {
+	return len(e.Changes) == 1 && e.Changes[0].Op == "snapshot"
+}
+
+// changes flattens instructions, the steps of a transaction are reported
+// one by one.
+func changes(inss []*pb.Instruction) []*pb.Change {
+	var cs []*pb.Change
+	for _, ins := range inss {
+		params := ins.GetParams()
+		c := &pb.Change{Op: strings.ToLower(ins.GetCode().String())}
+		switch ins.GetCode() {
+		case pb.Instruction_TX:
+			cs = append(cs, changes(ins.GetTx().GetInstructions())...)
+			continue
+		case pb.Instruction_CP, pb.Instruction_RESTORE, pb.Instruction_SYMLINK:
+			c.Path, c.Src = params[0], params[1]
+		case pb.Instruction_MV:
+			c.Src, c.Path = params[0], params[1]
+		default:
+			if len(params) > 0 {
+				c.Path = params[0]
+			}
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+type FeedServer struct {
+	feed *Feed
+}
+
+func NewFeedServer(feed *Feed) FeedServer {
+	return FeedServer{feed: feed}
+}
+
+func (s FeedServer) Subscribe(req *pb.SubscribeRequest, stream ChangeFeed_SubscribeServer) error {
+	events, err := s.feed.Subscribe(stream.Context(), req.GetFromIndex())
+	if err != nil {
+		return statusError(err)
+	}
+	for e := range events {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	if err := stream.Context().Err(); err != nil {
+		return err
+	}
+	return statusError(ErrFeedClosed)
+}
+
+func (s FeedServer) mustEmbedUnimplementedChangeFeedServer() {
+
+}
@@ -0,0 +1,59 @@
+package consensus
+
+import (
+	"context"
+	"github.com/gogo/protobuf/proto"
+	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/consensus/state"
+	"github.com/icetrays/icetrays/internal/testutil"
+	mdtest "github.com/ipfs/go-merkledag/test"
+	"testing"
+	"time"
+)
+
+func TestFeedResume(t *testing.T) {
+	st, err := state.NewFileTreeState(&testutil.MemStateDB{}, mdtest.Mock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsm := &Fsm{State: st, applied: make(chan struct{})}
+	logs := raft.NewInmemStore()
+	for i := uint64(1); i <= 3; i++ {
+		data, err := proto.Marshal(&pb.Instructions{
+			Instruction: []*pb.Instruction{{Code: pb.Instruction_MKDIR, Params: []string{"/d"}}},
+			Ctx:         &pb.Ctx{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := logs.StoreLog(&raft.Log{Index: i, Term: 1, Type: raft.LogCommand, Data: data}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st.SetIndex(3)
+	feed := NewFeed(fsm, logs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	events, err := feed.Subscribe(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsm.notify(Applied{Index: 3, Instructions: []*pb.Instruction{{Code: pb.Instruction_RM, Params: []string{"/d"}}}})
+	fsm.notify(Applied{Index: 4, Instructions: []*pb.Instruction{{Code: pb.Instruction_MV, Params: []string{"/a", "/b"}}}})
+
+	var got []*pb.Event
+	for e := range events {
+		got = append(got, e)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if len(got) != 3 || got[0].Index != 2 || got[1].Index != 3 || got[2].Index != 4 {
+		t.Fatalf("unexpected events: %v", got)
+	}
+	if c := got[2].Changes[0]; c.Op != "mv" || c.Src != "/a" || c.Path != "/b" {
+		t.Fatalf("unexpected change: %v", c)
+	}
+}
@@ -100,3 +100,396 @@ var RemoteExecute_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "consensus/pb/fs.proto",
 }
+
+// MembershipClient is the client API for Membership service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MembershipClient interface {
+	AddVoter(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error)
+	AddNonVoter(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error)
+	DemoteVoter(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error)
+	RemovePeer(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error)
+}
+
+type membershipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMembershipClient(cc grpc.ClientConnInterface) MembershipClient {
+	return &membershipClient{cc}
+}
+
+func (c *membershipClient) AddVoter(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error) {
+	out := new(pb.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Membership/AddVoter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *membershipClient) AddNonVoter(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error) {
+	out := new(pb.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Membership/AddNonVoter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *membershipClient) DemoteVoter(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error) {
+	out := new(pb.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Membership/DemoteVoter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *membershipClient) RemovePeer(ctx context.Context, in *pb.Peer, opts ...grpc.CallOption) (*pb.Empty, error) {
+	out := new(pb.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Membership/RemovePeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MembershipServer is the server API for Membership service.
+// All implementations must embed UnimplementedMembershipServer
+// for forward compatibility
+type MembershipServer interface {
+	AddVoter(context.Context, *pb.Peer) (*pb.Empty, error)
+	AddNonVoter(context.Context, *pb.Peer) (*pb.Empty, error)
+	DemoteVoter(context.Context, *pb.Peer) (*pb.Empty, error)
+	RemovePeer(context.Context, *pb.Peer) (*pb.Empty, error)
+	mustEmbedUnimplementedMembershipServer()
+}
+
+// UnimplementedMembershipServer must be embedded to have forward compatible implementations.
+type UnimplementedMembershipServer struct {
+}
+
+func (UnimplementedMembershipServer) AddVoter(context.Context, *pb.Peer) (*pb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddVoter not implemented")
+}
+func (UnimplementedMembershipServer) AddNonVoter(context.Context, *pb.Peer) (*pb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNonVoter not implemented")
+}
+func (UnimplementedMembershipServer) DemoteVoter(context.Context, *pb.Peer) (*pb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DemoteVoter not implemented")
+}
+func (UnimplementedMembershipServer) RemovePeer(context.Context, *pb.Peer) (*pb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePeer not implemented")
+}
+func (UnimplementedMembershipServer) mustEmbedUnimplementedMembershipServer() {}
+
+// UnsafeMembershipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MembershipServer will
+// result in compilation errors.
+type UnsafeMembershipServer interface {
+	mustEmbedUnimplementedMembershipServer()
+}
+
+func RegisterMembershipServer(s grpc.ServiceRegistrar, srv MembershipServer) {
+	s.RegisterService(&Membership_ServiceDesc, srv)
+}
+
+func _Membership_AddVoter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.Peer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MembershipServer).AddVoter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Membership/AddVoter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MembershipServer).AddVoter(ctx, req.(*pb.Peer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Membership_AddNonVoter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.Peer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MembershipServer).AddNonVoter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Membership/AddNonVoter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MembershipServer).AddNonVoter(ctx, req.(*pb.Peer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Membership_DemoteVoter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.Peer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MembershipServer).DemoteVoter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Membership/DemoteVoter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MembershipServer).DemoteVoter(ctx, req.(*pb.Peer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Membership_RemovePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.Peer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MembershipServer).RemovePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Membership/RemovePeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MembershipServer).RemovePeer(ctx, req.(*pb.Peer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Membership_ServiceDesc is the grpc.ServiceDesc for Membership service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Membership_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Membership",
+	HandlerType: (*MembershipServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddVoter",
+			Handler:    _Membership_AddVoter_Handler,
+		},
+		{
+			MethodName: "AddNonVoter",
+			Handler:    _Membership_AddNonVoter_Handler,
+		},
+		{
+			MethodName: "DemoteVoter",
+			Handler:    _Membership_DemoteVoter_Handler,
+		},
+		{
+			MethodName: "RemovePeer",
+			Handler:    _Membership_RemovePeer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "consensus/pb/fs.proto",
+}
+
+// ReaderClient is the client API for Reader service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReaderClient interface {
+	ReadIndex(ctx context.Context, in *pb.ReadIndexRequest, opts ...grpc.CallOption) (*pb.ReadIndexResponse, error)
+}
+
+type readerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReaderClient(cc grpc.ClientConnInterface) ReaderClient {
+	return &readerClient{cc}
+}
+
+func (c *readerClient) ReadIndex(ctx context.Context, in *pb.ReadIndexRequest, opts ...grpc.CallOption) (*pb.ReadIndexResponse, error) {
+	out := new(pb.ReadIndexResponse)
+	err := c.cc.Invoke(ctx, "/pb.Reader/ReadIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReaderServer is the server API for Reader service.
+// All implementations must embed UnimplementedReaderServer
+// for forward compatibility
+type ReaderServer interface {
+	ReadIndex(context.Context, *pb.ReadIndexRequest) (*pb.ReadIndexResponse, error)
+	mustEmbedUnimplementedReaderServer()
+}
+
+// UnimplementedReaderServer must be embedded to have forward compatible implementations.
+type UnimplementedReaderServer struct {
+}
+
+func (UnimplementedReaderServer) ReadIndex(context.Context, *pb.ReadIndexRequest) (*pb.ReadIndexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadIndex not implemented")
+}
+func (UnimplementedReaderServer) mustEmbedUnimplementedReaderServer() {}
+
+// UnsafeReaderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReaderServer will
+// result in compilation errors.
+type UnsafeReaderServer interface {
+	mustEmbedUnimplementedReaderServer()
+}
+
+func RegisterReaderServer(s grpc.ServiceRegistrar, srv ReaderServer) {
+	s.RegisterService(&Reader_ServiceDesc, srv)
+}
+
+func _Reader_ReadIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.ReadIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReaderServer).ReadIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Reader/ReadIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReaderServer).ReadIndex(ctx, req.(*pb.ReadIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Reader_ServiceDesc is the grpc.ServiceDesc for Reader service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Reader_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Reader",
+	HandlerType: (*ReaderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReadIndex",
+			Handler:    _Reader_ReadIndex_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "consensus/pb/fs.proto",
+}
+
+// ChangeFeedClient is the client API for ChangeFeed service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChangeFeedClient interface {
+	Subscribe(ctx context.Context, in *pb.SubscribeRequest, opts ...grpc.CallOption) (ChangeFeed_SubscribeClient, error)
+}
+
+type changeFeedClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChangeFeedClient(cc grpc.ClientConnInterface) ChangeFeedClient {
+	return &changeFeedClient{cc}
+}
+
+func (c *changeFeedClient) Subscribe(ctx context.Context, in *pb.SubscribeRequest, opts ...grpc.CallOption) (ChangeFeed_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ChangeFeed_ServiceDesc.Streams[0], "/pb.ChangeFeed/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &changeFeedSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChangeFeed_SubscribeClient interface {
+	Recv() (*pb.Event, error)
+	grpc.ClientStream
+}
+
+type changeFeedSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *changeFeedSubscribeClient) Recv() (*pb.Event, error) {
+	m := new(pb.Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ChangeFeedServer is the server API for ChangeFeed service.
+// All implementations must embed UnimplementedChangeFeedServer
+// for forward compatibility
+type ChangeFeedServer interface {
+	Subscribe(*pb.SubscribeRequest, ChangeFeed_SubscribeServer) error
+	mustEmbedUnimplementedChangeFeedServer()
+}
+
+// UnimplementedChangeFeedServer must be embedded to have forward compatible implementations.
+type UnimplementedChangeFeedServer struct {
+}
+
+func (UnimplementedChangeFeedServer) Subscribe(*pb.SubscribeRequest, ChangeFeed_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedChangeFeedServer) mustEmbedUnimplementedChangeFeedServer() {}
+
+// UnsafeChangeFeedServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChangeFeedServer will
+// result in compilation errors.
+type UnsafeChangeFeedServer interface {
+	mustEmbedUnimplementedChangeFeedServer()
+}
+
+func RegisterChangeFeedServer(s grpc.ServiceRegistrar, srv ChangeFeedServer) {
+	s.RegisterService(&ChangeFeed_ServiceDesc, srv)
+}
+
+func _ChangeFeed_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(pb.SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChangeFeedServer).Subscribe(m, &changeFeedSubscribeServer{stream})
+}
+
+type ChangeFeed_SubscribeServer interface {
+	Send(*pb.Event) error
+	grpc.ServerStream
+}
+
+type changeFeedSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *changeFeedSubscribeServer) Send(m *pb.Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ChangeFeed_ServiceDesc is the grpc.ServiceDesc for ChangeFeed service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChangeFeed_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.ChangeFeed",
+	HandlerType: (*ChangeFeedServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _ChangeFeed_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "consensus/pb/fs.proto",
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/icetrays/icetrays/datastore"
 	httpapi "github.com/ipfs/go-ipfs-http-client"
 	"github.com/ipfs/go-log/v2"
+	"github.com/prometheus/client_golang/prometheus"
 	"io"
+	"sync"
 )
 
 var ErrInconsistent = errors.New("inconsistent")
@@ -21,11 +23,26 @@ func init() {
 	log.SetLogLevel("fsm", "debug")
 }
 
+type Applied struct {
+	Index        uint64
+	Term         uint64
+	Pre          string
+	Next         string
+	Instructions []*pb.Instruction
+	// Restored is set when the state was replaced by a snapshot.
+	Restored bool
+	// Attrs are the attributes of the tree at Next, by path.
+	Attrs map[string]state.Attrs
+}
+
 type Fsm struct {
 	client       *httpapi.HttpApi
 	State        *state.FileTreeState
 	ctx          context.Context
 	inconsistent bool
+	mtx          sync.Mutex
+	listeners    []func(Applied)
+	applied      chan struct{}
 }
 
 func NewFsm(store *datastore.BadgerDB, api *httpapi.HttpApi) (*Fsm, error) {
@@ -38,6 +55,7 @@ func NewFsm(store *datastore.BadgerDB, api *httpapi.HttpApi) (*Fsm, error) {
 		State:        _state,
 		ctx:          context.Background(),
 		inconsistent: false,
+		applied:      make(chan struct{}),
 	}, nil
 }
 
@@ -45,9 +63,11 @@ func (f *Fsm) Apply(log *raft.Log) interface{} {
 	if log.Type != raft.LogCommand {
 		return nil
 	}
+	defer prometheus.NewTimer(applyDuration).ObserveDuration()
 	var err error
 	index := f.State.Index()
 	if log.Index < index {
+		inconsistencies.Inc()
 		f.inconsistent = true
 		return ErrInconsistent
 	} else if log.Index == index {
@@ -67,15 +87,17 @@ func (f *Fsm) Apply(log *raft.Log) interface{} {
 		return nil
 	}
 	snapshot := f.State.Lock()
+	f.State.SetApplying(log.Index)
 	var leader bool
 	for {
 		var err error
-		if f.State.MustGetRoot() == inss.Ctx.Next {
+		if f.State.MustGetRoot() == inss.Ctx.Next && !besideTree(inss.Instruction) {
 			leader = true
 
 		} else {
 			err = commitFunction(inss.Instruction)
 			if err != nil {
+				applyRollbacks.Inc()
 				f.State.MustRollBack(snapshot)
 				continue
 			}
@@ -91,19 +113,94 @@ func (f *Fsm) Apply(log *raft.Log) interface{} {
 	}
 	after := f.State.UnLock()
 	if (snapshot.Root != inss.Ctx.Pre && !leader) || after.Root != inss.Ctx.Next {
+		inconsistencies.Inc()
 		logger.Warnf("inconsistent: want: %s->%s, got %s->%s", inss.Ctx.Pre, inss.Ctx.Next, snapshot.Root, after.Root)
 		//_ = f.State.Unmarshal(strings.NewReader(inss.Ctx.Next))
 	}
+	f.notify(Applied{
+		Index:        log.Index,
+		Term:         log.Term,
+		Pre:          snapshot.Root,
+		Next:         after.Root,
+		Instructions: inss.Instruction,
+		Attrs:        after.Attrs,
+	})
 	return nil
 }
 
+// besideTree reports whether inss change tags, attributes, quotas or ACLs, which
+// leave the root as it is and so must be executed even when the root
+// already is the expected one.
+func besideTree(inss []*pb.Instruction) bool {
+	for _, ins := range inss {
+		switch ins.GetCode() {
+		case pb.Instruction_TAG, pb.Instruction_UNTAG, pb.Instruction_SETATTR, pb.Instruction_QUOTA, pb.Instruction_ACL:
+			return true
+		case pb.Instruction_TX:
+			if besideTree(ins.GetTx().GetInstructions()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OnApplied registers fn to be called after every committed log entry and
+// after a snapshot restore. Listeners run on the raft apply goroutine, so
+// they must not block.
+func (f *Fsm) OnApplied(fn func(Applied)) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.listeners = append(f.listeners, fn)
+}
+
+// WaitApplied blocks until the state has applied the log entry at index.
+func (f *Fsm) WaitApplied(ctx context.Context, index uint64) error {
+	for {
+		f.mtx.Lock()
+		ch := f.applied
+		f.mtx.Unlock()
+		if f.State.Index() >= index {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (f *Fsm) notify(applied Applied) {
+	f.mtx.Lock()
+	listeners := f.listeners
+	close(f.applied)
+	f.applied = make(chan struct{})
+	f.mtx.Unlock()
+	for _, fn := range listeners {
+		fn(applied)
+	}
+}
+
 func (f *Fsm) Snapshot() (raft.FSMSnapshot, error) {
 	return &Snapshot{state: f.State}, nil
 }
 
 func (f *Fsm) Restore(closer io.ReadCloser) error {
 	defer closer.Close()
-	return f.State.Unmarshal(closer)
+	pre := f.State.MustGetRoot()
+	if err := f.State.Unmarshal(closer); err != nil {
+		return err
+	}
+	ss := f.State.SnapShot()
+	f.notify(Applied{
+		Index:    ss.Index,
+		Pre:      pre,
+		Next:     ss.Root,
+		Restored: true,
+		Attrs:    ss.Attrs,
+	})
+	return nil
 }
 
 func (f *Fsm) Inconsistent() bool {
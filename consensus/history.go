@@ -0,0 +1,200 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/state"
+	"github.com/icetrays/icetrays/datastore"
+	"github.com/ipfs/go-cid"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidVersion  = errors.New("invalid version")
+	ErrVersionNotFound = errors.New("version not found")
+)
+
+// History records the root of the tree after every applied entry, and its
+// attributes when they changed, so that earlier versions can be browsed.
+// Watchers registered with OnRetained learn which roots the retained
+// versions have, the pinner keeps them pinned until they are pruned.
+type History struct {
+	db       *datastore.HistoryDB
+	state    *state.FileTreeState
+	mtx      sync.Mutex
+	roots    map[string]int
+	attrs    []byte
+	watchers []func(root string, retained bool)
+}
+
+func NewHistory(fsm *Fsm, db *datastore.HistoryDB) (*History, error) {
+	h := &History{db: db, state: fsm.State, roots: make(map[string]int)}
+	versions, err := db.Versions(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		h.roots[v.Root]++
+		if h.attrs == nil && len(v.Attrs) > 0 {
+			h.attrs = v.Attrs
+		}
+	}
+	fsm.OnApplied(h.record)
+	return h, nil
+}
+
+func (h *History) record(applied Applied) {
+	v := datastore.Version{Index: applied.Index, Root: applied.Next, Time: time.Now()}
+	attrs := applied.Attrs
+	if attrs == nil {
+		attrs = map[string]state.Attrs{}
+	}
+	// map keys are sorted, equal attributes encode the same
+	encoded, err := json.Marshal(attrs)
+	if err != nil {
+		logger.Errorf("record attributes of version %d: %s", applied.Index, err)
+	} else if !bytes.Equal(encoded, h.attrs) {
+		v.Attrs = encoded
+	}
+	if err := h.db.Add(v); err != nil {
+		logger.Errorf("record version %d: %s", applied.Index, err)
+		return
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if v.Attrs != nil {
+		h.attrs = v.Attrs
+	}
+	h.roots[v.Root]++
+	if h.roots[v.Root] == 1 {
+		h.notify(v.Root, true)
+	}
+}
+
+// OnRetained calls fn with every root of the retained versions and from then
+// on whenever a root is first recorded or its last version is pruned.
+func (h *History) OnRetained(fn func(root string, retained bool)) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.watchers = append(h.watchers, fn)
+	for root := range h.roots {
+		fn(root, true)
+	}
+}
+
+func (h *History) notify(root string, retained bool) {
+	for _, fn := range h.watchers {
+		fn(root, retained)
+	}
+}
+
+// Resolve finds the version at is referring to: a tag name, a raft index, an
+// RFC 3339 timestamp or a root CID. Tags take precedence.
+func (h *History) Resolve(at string) (datastore.Version, error) {
+	var v datastore.Version
+	var err error
+	if t, terr := h.state.Tag(at); terr == nil {
+		// tagged versions are kept, older tags may predate the history
+		if v, err := h.db.At(t.Index); err == nil && v.Root == t.Root {
+			return v, nil
+		}
+		return datastore.Version{Index: t.Index, Root: t.Root, Time: t.Created}, nil
+	}
+	if index, perr := strconv.ParseUint(at, 10, 64); perr == nil {
+		v, err = h.db.At(index)
+	} else if t, perr := time.Parse(time.RFC3339, at); perr == nil {
+		v, err = h.db.Before(t)
+	} else if c, perr := cid.Decode(at); perr == nil {
+		v, err = h.db.ByRoot(c.String())
+	} else {
+		return v, fmt.Errorf("%w: %q is not an index, RFC 3339 time or CID", ErrInvalidVersion, at)
+	}
+	if err == datastore.ErrKeyNotFound {
+		return v, fmt.Errorf("%w: %s", ErrVersionNotFound, at)
+	}
+	return v, err
+}
+
+// View returns a read-only state of the tree at the version at refers to.
+func (h *History) View(ctx context.Context, at string) (*state.FileTreeState, error) {
+	v, err := h.Resolve(at)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cid.Decode(v.Root)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := h.db.AttrsAt(v.Index)
+	if err != nil {
+		return nil, err
+	}
+	var attrs map[string]state.Attrs
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &attrs); err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return h.state.View(ctx, c, attrs)
+}
+
+// Versions lists up to limit versions newest first, without their
+// attributes.
+func (h *History) Versions(limit int) ([]datastore.Version, error) {
+	versions, err := h.db.Versions(limit)
+	for i := range versions {
+		versions[i].Attrs = nil
+	}
+	return versions, err
+}
+
+// Retain prunes the history every minute until ctx is done, keeping at most
+// keep versions no older than maxAge. Zero disables the respective limit.
+func (h *History) Retain(ctx context.Context, keep int, maxAge time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pruned, err := h.db.Prune(keep, maxAge, h.tagged)
+			if err != nil {
+				logger.Errorf("prune history: %s", err)
+			} else if len(pruned) > 0 {
+				logger.Debugf("pruned %d versions", len(pruned))
+				h.release(pruned)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// release forgets the roots of the pruned versions that no retained version
+// has anymore.
+func (h *History) release(pruned []datastore.Version) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for _, v := range pruned {
+		if h.roots[v.Root]--; h.roots[v.Root] <= 0 {
+			delete(h.roots, v.Root)
+			h.notify(v.Root, false)
+		}
+	}
+}
+
+// tagged keeps the versions of tagged roots from being pruned.
+func (h *History) tagged(v datastore.Version) bool {
+	for _, root := range h.state.TaggedRoots() {
+		if v.Root == root {
+			return true
+		}
+	}
+	return false
+}
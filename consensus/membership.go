@@ -0,0 +1,76 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var ErrInvalidPeer = errors.New("invalid peer id")
+
+type Membership interface {
+	AddVoter(id string) error
+	AddNonVoter(id string) error
+	DemoteVoter(id string) error
+	RemovePeer(id string) error
+}
+
+type Peer struct {
+	ID       string `json:"id"`
+	Suffrage string `json:"suffrage"`
+	Leader   bool   `json:"leader"`
+}
+
+type raftMembership struct {
+	r *raft.Raft
+}
+
+func (m raftMembership) AddVoter(id string) error {
+	return m.r.AddVoter(raft.ServerID(id), raft.ServerAddress(id), 0, defaultTimeout).Error()
+}
+
+func (m raftMembership) AddNonVoter(id string) error {
+	return m.r.AddNonvoter(raft.ServerID(id), raft.ServerAddress(id), 0, defaultTimeout).Error()
+}
+
+func (m raftMembership) DemoteVoter(id string) error {
+	return m.r.DemoteVoter(raft.ServerID(id), 0, defaultTimeout).Error()
+}
+
+func (m raftMembership) RemovePeer(id string) error {
+	return m.r.RemoveServer(raft.ServerID(id), 0, defaultTimeout).Error()
+}
+
+type MembershipOpServer struct {
+	members Membership
+}
+
+func (m MembershipOpServer) AddVoter(ctx context.Context, p *pb.Peer) (*pb.Empty, error) {
+	return &pb.Empty{}, statusError(m.members.AddVoter(p.GetId()))
+}
+
+func (m MembershipOpServer) AddNonVoter(ctx context.Context, p *pb.Peer) (*pb.Empty, error) {
+	return &pb.Empty{}, statusError(m.members.AddNonVoter(p.GetId()))
+}
+
+func (m MembershipOpServer) DemoteVoter(ctx context.Context, p *pb.Peer) (*pb.Empty, error) {
+	return &pb.Empty{}, statusError(m.members.DemoteVoter(p.GetId()))
+}
+
+func (m MembershipOpServer) RemovePeer(ctx context.Context, p *pb.Peer) (*pb.Empty, error) {
+	return &pb.Empty{}, statusError(m.members.RemovePeer(p.GetId()))
+}
+
+func (m MembershipOpServer) mustEmbedUnimplementedMembershipServer() {
+
+}
+
+func checkPeerID(id string) error {
+	if _, err := peer.Decode(id); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPeer, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package consensus
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// durationBuckets suit latencies in seconds, from a millisecond to a minute.
+var durationBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	packerBatchSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "icetrays_packer_batch_size",
+		Help:    "Instructions committed together by the op packer.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+	packerQueueWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "icetrays_packer_queue_wait_seconds",
+		Help:    "Time an instruction waits in the op packer before its batch is committed.",
+		Buckets: durationBuckets,
+	})
+	preCommitDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "icetrays_precommit_duration_seconds",
+		Help:    "Time the leader takes to pre-execute and commit a batch.",
+		Buckets: durationBuckets,
+	})
+	applyDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "icetrays_fsm_apply_duration_seconds",
+		Help:    "Time the fsm takes to apply a log entry.",
+		Buckets: durationBuckets,
+	})
+	applyRollbacks = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "icetrays_fsm_rollbacks_total",
+		Help: "Log entries whose execution failed and was rolled back before retrying.",
+	})
+	inconsistencies = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "icetrays_fsm_inconsistencies_total",
+		Help: "Log entries applied out of order or whose roots differ from the leader's.",
+	})
+	dagFetchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "icetrays_ipfs_dag_fetch_duration_seconds",
+		Help:    "Time taken to fetch the node of a copied CID from IPFS.",
+		Buckets: durationBuckets,
+	})
+	dagFetchErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "icetrays_ipfs_dag_fetch_errors_total",
+		Help: "Failed fetches of the node of a copied CID from IPFS.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		packerBatchSize,
+		packerQueueWait,
+		preCommitDuration,
+		applyDuration,
+		applyRollbacks,
+		inconsistencies,
+		dagFetchDuration,
+		dagFetchErrors,
+	)
+}
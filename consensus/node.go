@@ -5,17 +5,23 @@ import (
 	"errors"
 	"github.com/hashicorp/raft"
 	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/consensus/state"
 	"github.com/icetrays/icetrays/network"
 	"github.com/ipfs/go-cid"
+	files "github.com/ipfs/go-ipfs-files"
 	httpapi "github.com/ipfs/go-ipfs-http-client"
 	"github.com/ipfs/go-mfs"
-	gostream "github.com/libp2p/go-libp2p-gostream"
+	uio "github.com/ipfs/go-unixfs/io"
+	"github.com/ipfs/interface-go-ipfs-core/options"
 	"google.golang.org/grpc"
+	"io"
 	"strings"
 	"sync"
 	"time"
 )
 
+var ErrNoOperator = errors.New("no matched operator")
+
 type Node struct {
 	raft       preCommitter
 	fsm        *Fsm
@@ -29,9 +35,19 @@ type Node struct {
 	packer     Sender
 }
 
+// Op applies an instruction without flags, see pb.Flags.
 func (n *Node) Op(ctx context.Context, code pb.Instruction_Code, params ...string) error {
+	return n.Exec(ctx, &pb.Instruction{Code: code, Params: params})
+}
+
+// Exec applies ins, its node data is fetched from IPFS when it needs any.
+func (n *Node) Exec(ctx context.Context, ins *pb.Instruction) error {
 	if n.fsm.Inconsistent() {
-		return errors.New("inconsistent state")
+		return ErrInconsistent
+	}
+	code, params := ins.GetCode(), ins.GetParams()
+	if err := checkParams(code, params); err != nil {
+		return err
 	}
 
 	err := n.TrySwitchOperator()
@@ -40,40 +56,323 @@ func (n *Node) Op(ctx context.Context, code pb.Instruction_Code, params ...strin
 	}
 	switch code {
 	case pb.Instruction_CP:
-		if strings.HasPrefix(params[1], "/") {
-			return n.operator.Cp(ctx, params[0], params[1], nil)
-		} else {
-			c, err := cid.Decode(params[1])
-			if err != nil {
-				return err
-			}
-			cctx, cancel := context.WithTimeout(ctx, time.Second*20)
-			defer cancel()
-			ipldNode, err := n.ipfs.Dag().Get(cctx, c)
-			if err != nil {
-				return err
-			}
-			return n.operator.Cp(ctx, params[0], params[1], ipldNode.RawData())
+		nodeData, err := n.nodeData(ctx, params[1])
+		if err != nil {
+			return err
 		}
+		return n.operator.Cp(ctx, params[0], params[1], nodeData, ins.GetFlags())
 	case pb.Instruction_MV:
-		return n.operator.Mv(ctx, params[0], params[1])
+		return n.operator.Mv(ctx, params[0], params[1], ins.GetFlags())
 	case pb.Instruction_RM:
-		return n.operator.Rm(ctx, params[0])
+		return n.operator.Rm(ctx, params[0], ins.GetFlags())
 	case pb.Instruction_MKDIR:
 		return n.operator.MkDir(ctx, params[0])
+	case pb.Instruction_RESTORE:
+		if strings.HasPrefix(params[1], "/") {
+			return state.ErrInvalidPath
+		}
+		nodeData, err := n.nodeData(ctx, params[1])
+		if err != nil {
+			return err
+		}
+		return n.operator.Restore(ctx, params[0], params[1], nodeData, ins.GetRestored())
+	case pb.Instruction_TAG:
+		return n.operator.Tag(ctx, params[0], params[1])
+	case pb.Instruction_UNTAG:
+		return n.operator.Untag(ctx, params[0])
+	case pb.Instruction_SYMLINK:
+		return n.operator.Symlink(ctx, params[0], params[1])
+	case pb.Instruction_SETATTR:
+		if err := state.CheckAttrs(ins.GetAttrs()); err != nil {
+			return err
+		}
+		return n.operator.SetAttr(ctx, params[0], ins.GetAttrs())
+	case pb.Instruction_QUOTA:
+		return n.operator.SetQuota(ctx, params[0], ins.GetQuota())
+	case pb.Instruction_ACL:
+		if err := state.CheckACL(ins.GetAcl()); err != nil {
+			return err
+		}
+		return n.operator.SetACL(ctx, params[0], ins.GetAcl())
 	default:
-		return errors.New("no matched operator")
+		return ErrNoOperator
+	}
+}
+
+// Write adds the content of r to the IPFS node and copies it to path in the
+// tree. The content stays pinned only if the copy is committed.
+func (n *Node) Write(ctx context.Context, path string, r io.Reader, opts ...options.UnixfsAddOption) (cid.Cid, error) {
+	return n.WriteIf(ctx, nil, path, r, opts...)
+}
+
+// WriteIf is Write that only commits the copy if every precondition holds.
+func (n *Node) WriteIf(ctx context.Context, preconditions []*pb.Precondition, path string, r io.Reader, opts ...options.UnixfsAddOption) (cid.Cid, error) {
+	if _, err := state.CheckPath(path); err != nil {
+		return cid.Undef, err
+	}
+	opts = append(opts, options.Unixfs.Pin(true))
+	resolved, err := n.ipfs.Unixfs().Add(ctx, files.NewReaderFile(r), opts...)
+	if err != nil {
+		return cid.Undef, err
+	}
+	c := resolved.Cid()
+	if err := n.OpIf(ctx, preconditions, pb.Instruction_CP, path, c.String()); err != nil {
+		if err := n.ipfs.Pin().Rm(n.ctx, resolved); err != nil {
+			logger.Warnf("unpin %s: %s", c, err)
+		}
+		return cid.Undef, err
+	}
+	return c, nil
+}
+
+// OpIf applies the instruction only if every precondition holds. A CID_EQUALS
+// precondition on "/" turns it into a compare-and-swap on the root.
+func (n *Node) OpIf(ctx context.Context, preconditions []*pb.Precondition, code pb.Instruction_Code, params ...string) error {
+	return n.ExecIf(ctx, preconditions, &pb.Instruction{Code: code, Params: params})
+}
+
+// ExecIf is Exec that only applies ins if every precondition holds.
+func (n *Node) ExecIf(ctx context.Context, preconditions []*pb.Precondition, ins *pb.Instruction) error {
+	if len(preconditions) == 0 {
+		return n.Exec(ctx, ins)
+	}
+	return n.Transact(ctx, &pb.Transaction{
+		Preconditions: preconditions,
+		Instructions:  []*pb.Instruction{ins},
+	})
+}
+
+// Transact submits the instructions of tx as a single log entry, they are
+// applied only if every precondition holds and none of them fails.
+func (n *Node) Transact(ctx context.Context, tx *pb.Transaction) error {
+	if n.fsm.Inconsistent() {
+		return ErrInconsistent
+	}
+	for _, ins := range tx.GetInstructions() {
+		if ins.GetCode() == pb.Instruction_TX {
+			return state.ErrNestedTransaction
+		}
+		if err := checkParams(ins.GetCode(), ins.GetParams()); err != nil {
+			return err
+		}
+		if ins.GetCode() == pb.Instruction_CP || ins.GetCode() == pb.Instruction_RESTORE {
+			nodeData, err := n.nodeData(ctx, ins.GetParams()[1])
+			if err != nil {
+				return err
+			}
+			ins.Node = nodeData
+		}
+	}
+	if err := n.TrySwitchOperator(); err != nil {
+		return err
+	}
+	return n.operator.Transact(ctx, tx)
+}
+
+// nodeData fetches the raw block of a CP source given as a CID, sources that
+// are tree paths need none.
+func (n *Node) nodeData(ctx context.Context, src string) ([]byte, error) {
+	if strings.HasPrefix(src, "/") {
+		return nil, nil
+	}
+	c, err := cid.Decode(src)
+	if err != nil {
+		return nil, err
+	}
+	cctx, cancel := context.WithTimeout(ctx, time.Second*20)
+	defer cancel()
+	start := time.Now()
+	ipldNode, err := n.ipfs.Dag().Get(cctx, c)
+	dagFetchDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		dagFetchErrors.Inc()
+		return nil, err
+	}
+	return ipldNode.RawData(), nil
+}
+
+func checkParams(code pb.Instruction_Code, params []string) error {
+	want := 1
+	switch code {
+	case pb.Instruction_CP, pb.Instruction_MV, pb.Instruction_RESTORE, pb.Instruction_TAG, pb.Instruction_SYMLINK:
+		want = 2
+	}
+	if len(params) != want {
+		return state.ErrParamsNum
+	}
+	return nil
+}
+
+// Tag names the current root, the tag keeps it pinned and in the history.
+func (n *Node) Tag(ctx context.Context, name string) error {
+	if err := state.CheckTagName(name); err != nil {
+		return err
+	}
+	return n.Op(ctx, pb.Instruction_TAG, name, time.Now().UTC().Format(time.RFC3339Nano))
+}
+
+func (n *Node) Untag(ctx context.Context, name string) error {
+	return n.Op(ctx, pb.Instruction_UNTAG, name)
+}
+
+func (n *Node) Tags(ctx context.Context, consistency Consistency) ([]state.Tag, error) {
+	if err := n.waitRead(ctx, consistency); err != nil {
+		return nil, err
 	}
+	return n.fsm.State.Tags(), nil
 }
 
-func (n *Node) Ls(ctx context.Context, path string) ([]mfs.NodeListing, error) {
+func (n *Node) Ls(ctx context.Context, path string, consistency Consistency) ([]mfs.NodeListing, error) {
+	if err := n.waitRead(ctx, consistency); err != nil {
+		return nil, err
+	}
 	return n.fsm.State.Ls(ctx, path)
 }
 
+func (n *Node) Stat(ctx context.Context, path string, consistency Consistency) (*state.Stat, error) {
+	if err := n.waitRead(ctx, consistency); err != nil {
+		return nil, err
+	}
+	return n.fsm.State.Stat(ctx, path)
+}
+
+func (n *Node) Tree(ctx context.Context, path string, depth int, consistency Consistency) (*state.Tree, error) {
+	if err := n.waitRead(ctx, consistency); err != nil {
+		return nil, err
+	}
+	return n.fsm.State.Tree(ctx, path, depth)
+}
+
+// ReadState returns the local state once it is as fresh as consistency asks.
+func (n *Node) ReadState(ctx context.Context, consistency Consistency) (*state.FileTreeState, error) {
+	if err := n.waitRead(ctx, consistency); err != nil {
+		return nil, err
+	}
+	return n.fsm.State, nil
+}
+
+// Open returns a seekable reader over the file at path.
+func (n *Node) Open(ctx context.Context, path string, consistency Consistency) (uio.DagReader, error) {
+	if err := n.waitRead(ctx, consistency); err != nil {
+		return nil, err
+	}
+	return n.fsm.State.Open(ctx, path)
+}
+
+// Read streams length bytes of the file at path starting at offset from the
+// local state, a negative length reads to the end of the file.
+func (n *Node) Read(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	r, err := n.Open(ctx, path, Stale)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || uint64(offset) > r.Size() {
+		r.Close()
+		return nil, state.ErrOutOfRange
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		r.Close()
+		return nil, err
+	}
+	if length < 0 {
+		return r, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(r, length), r}, nil
+}
+
+// waitRead asks the leader for its read index and waits until the local
+// state has caught up with it. Stale reads return immediately.
+func (n *Node) waitRead(ctx context.Context, consistency Consistency) error {
+	if consistency == Stale || consistency == "" {
+		return nil
+	}
+	if err := n.TrySwitchOperator(); err != nil {
+		return err
+	}
+	index, err := n.operator.ReadIndex(ctx, consistency == Linearizable)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return n.fsm.WaitApplied(ctx, index)
+}
+
+func (n *Node) AddVoter(ctx context.Context, id string) error {
+	return n.changeMembership(id, func(op Operator) error { return op.AddVoter(ctx, id) })
+}
+
+func (n *Node) AddNonVoter(ctx context.Context, id string) error {
+	return n.changeMembership(id, func(op Operator) error { return op.AddNonVoter(ctx, id) })
+}
+
+func (n *Node) DemoteVoter(ctx context.Context, id string) error {
+	return n.changeMembership(id, func(op Operator) error { return op.DemoteVoter(ctx, id) })
+}
+
+func (n *Node) RemovePeer(ctx context.Context, id string) error {
+	return n.changeMembership(id, func(op Operator) error { return op.RemovePeer(ctx, id) })
+}
+
+func (n *Node) changeMembership(id string, change func(op Operator) error) error {
+	if err := checkPeerID(id); err != nil {
+		return err
+	}
+	if err := n.TrySwitchOperator(); err != nil {
+		return err
+	}
+	return change(n.operator)
+}
+
+func (n *Node) Peers() ([]Peer, error) {
+	future := n.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return nil, err
+	}
+	leader := n.Leader()
+	servers := future.Configuration().Servers
+	peers := make([]Peer, len(servers))
+	for i, server := range servers {
+		peers[i] = Peer{
+			ID:       string(server.ID),
+			Suffrage: server.Suffrage.String(),
+			Leader:   string(server.Address) == leader,
+		}
+	}
+	return peers, nil
+}
+
+type Status struct {
+	ID           string `json:"id"`
+	Leader       string `json:"leader"`
+	State        string `json:"state"`
+	Root         string `json:"root"`
+	Index        uint64 `json:"index"`
+	Inconsistent bool   `json:"inconsistent"`
+}
+
+func (n *Node) Status() Status {
+	return Status{
+		ID:           n.ID,
+		Leader:       n.Leader(),
+		State:        n.raft.State().String(),
+		Root:         n.fsm.State.MustGetRoot(),
+		Index:        n.fsm.State.Index(),
+		Inconsistent: n.fsm.Inconsistent(),
+	}
+}
+
 func (n *Node) Leader() string {
 	return string(n.raft.Leader())
 }
 
+func (n *Node) IsLeader() bool {
+	return n.ID == n.Leader()
+}
+
 func (n *Node) Operator() string {
 	return n.operator.Address()
 }
@@ -95,7 +394,7 @@ func (n *Node) TrySwitchOperator() error {
 
 func (n *Node) SwitchOperator() error {
 	if n.ID == n.Leader() {
-		n.operator = NewLocalOperator(n.packer, n.ID)
+		n.operator = NewLocalOperator(n.packer, raftMembership{n.raft.Raft}, raftReader{n.raft.Raft, n.fsm.State}, n.ID)
 	} else {
 		conn, err := n.network.Connect(n.ctx, n.Leader())
 		if err != nil {
@@ -106,7 +405,7 @@ func (n *Node) SwitchOperator() error {
 	return nil
 }
 
-func NewNode(ctx context.Context, r *raft.Raft, fsm *Fsm, id string, net *network.Network, ipfs *httpapi.HttpApi) (*Node, error) {
+func NewNode(ctx context.Context, r *raft.Raft, fsm *Fsm, id string, net *network.Network, ipfs *httpapi.HttpApi, server *grpc.Server) (*Node, error) {
 	node := &Node{
 		raft:       preCommitter{r, fsm.State},
 		fsm:        fsm,
@@ -119,15 +418,11 @@ func NewNode(ctx context.Context, r *raft.Raft, fsm *Fsm, id string, net *networ
 		ipfs:       ipfs,
 	}
 	err := node.SwitchOperator()
-	listener, err := gostream.Listen(net.Host(), network.Protocol)
-	if err != nil {
-		return nil, err
-	}
 	packer := NewPacker(node.raft, time.Millisecond*300, 100)
 	node.packer = packer
-	s1 := grpc.NewServer()
 
-	RegisterRemoteExecuteServer(s1, FsOpServer{operator: packer})
-	go s1.Serve(listener)
+	RegisterRemoteExecuteServer(server, FsOpServer{operator: packer})
+	RegisterMembershipServer(server, MembershipOpServer{members: raftMembership{r}})
+	RegisterReaderServer(server, ReadIndexServer{reader: raftReader{r, fsm.State}})
 	return node, err
 }
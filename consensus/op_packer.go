@@ -14,9 +14,10 @@ type Caller interface {
 }
 
 type FileOpRequest struct {
-	done chan *FileOpRequest
-	ins  *pb.Instruction
-	err  error
+	done   chan *FileOpRequest
+	ins    *pb.Instruction
+	err    error
+	queued time.Time
 }
 
 type OpPacker struct {
@@ -47,9 +48,10 @@ func (packer *OpPacker) send(ins *pb.Instruction) (*FileOpRequest, error) {
 	packer.mtx.Unlock()
 	done := packer.chanPool.Get().(chan *FileOpRequest)
 	call := &FileOpRequest{
-		done: done,
-		ins:  ins,
-		err:  nil,
+		done:   done,
+		ins:    ins,
+		err:    nil,
+		queued: time.Now(),
 	}
 	packer.request <- call
 	return call, nil
@@ -85,7 +87,9 @@ func (packer *OpPacker) Commit() {
 		inss := make([]*pb.Instruction, len(packer.cache))
 		for index, call := range packer.cache {
 			inss[index] = call.ins
+			packerQueueWait.Observe(time.Since(call.queued).Seconds())
 		}
+		packerBatchSize.Observe(float64(len(inss)))
 		errs := packer.caller.Call(inss)
 		for index, call := range packer.cache {
 			call.err = errs[index]
@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"context"
+	"github.com/icetrays/icetrays/auth"
 	"github.com/icetrays/icetrays/consensus/pb"
 	"google.golang.org/grpc"
 	"time"
@@ -10,10 +11,23 @@ import (
 const defaultTimeout = time.Second * 5
 
 type Operator interface {
-	Cp(ctx context.Context, dir, path string, nodeData []byte) error
-	Mv(ctx context.Context, dir, path string) error
-	Rm(ctx context.Context, path string) error
+	Cp(ctx context.Context, dir, path string, nodeData []byte, flags *pb.Flags) error
+	Mv(ctx context.Context, dir, path string, flags *pb.Flags) error
+	Rm(ctx context.Context, path string, flags *pb.Flags) error
 	MkDir(ctx context.Context, path string) error
+	Transact(ctx context.Context, tx *pb.Transaction) error
+	Restore(ctx context.Context, path, c string, nodeData []byte, attrs map[string]*pb.Attributes) error
+	Tag(ctx context.Context, name, created string) error
+	Untag(ctx context.Context, name string) error
+	Symlink(ctx context.Context, path, target string) error
+	SetAttr(ctx context.Context, path string, attrs *pb.Attributes) error
+	SetQuota(ctx context.Context, path string, quota *pb.Quota) error
+	SetACL(ctx context.Context, prefix string, acl *pb.Acl) error
+	AddVoter(ctx context.Context, id string) error
+	AddNonVoter(ctx context.Context, id string) error
+	DemoteVoter(ctx context.Context, id string) error
+	RemovePeer(ctx context.Context, id string) error
+	ReadIndex(ctx context.Context, verify bool) (uint64, error)
 	Address() string
 }
 
@@ -22,92 +36,311 @@ type Sender interface {
 }
 
 type LocalOperator struct {
-	sender Sender
-	addr   string
+	sender  Sender
+	members Membership
+	reader  ReadIndexer
+	addr    string
 }
 
-func (l *LocalOperator) Cp(ctx context.Context, dir, path string, nodeData []byte) error {
-	return l.operation(pb.Instruction_CP, nil, dir, path)
+func (l *LocalOperator) Cp(ctx context.Context, dir, path string, nodeData []byte, flags *pb.Flags) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:   pb.Instruction_CP,
+		Params: []string{dir, path},
+		Node:   nodeData,
+		Flags:  flags,
+	})
 }
 
-func (l *LocalOperator) Mv(ctx context.Context, dir, path string) error {
-	return l.operation(pb.Instruction_MV, nil, dir, path)
+func (l *LocalOperator) Mv(ctx context.Context, dir, path string, flags *pb.Flags) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:   pb.Instruction_MV,
+		Params: []string{dir, path},
+		Flags:  flags,
+	})
 }
 
-func (l *LocalOperator) Rm(ctx context.Context, path string) error {
-	return l.operation(pb.Instruction_RM, nil, path)
+func (l *LocalOperator) Rm(ctx context.Context, path string, flags *pb.Flags) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:   pb.Instruction_RM,
+		Params: []string{path},
+		Flags:  flags,
+	})
 }
 
 func (l *LocalOperator) MkDir(ctx context.Context, path string) error {
-	return l.operation(pb.Instruction_MKDIR, nil, path)
+	return l.operation(ctx, pb.Instruction_MKDIR, nil, path)
+}
+
+func (l *LocalOperator) Transact(ctx context.Context, tx *pb.Transaction) error {
+	return l.send(ctx, &pb.Instruction{Code: pb.Instruction_TX, Tx: tx})
+}
+
+func (l *LocalOperator) Restore(ctx context.Context, path, c string, nodeData []byte, attrs map[string]*pb.Attributes) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:     pb.Instruction_RESTORE,
+		Params:   []string{path, c},
+		Node:     nodeData,
+		Restored: attrs,
+	})
+}
+
+func (l *LocalOperator) Tag(ctx context.Context, name, created string) error {
+	return l.operation(ctx, pb.Instruction_TAG, nil, name, created)
+}
+
+func (l *LocalOperator) Untag(ctx context.Context, name string) error {
+	return l.operation(ctx, pb.Instruction_UNTAG, nil, name)
+}
+
+func (l *LocalOperator) Symlink(ctx context.Context, path, target string) error {
+	return l.operation(ctx, pb.Instruction_SYMLINK, nil, path, target)
+}
+
+func (l *LocalOperator) SetAttr(ctx context.Context, path string, attrs *pb.Attributes) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:   pb.Instruction_SETATTR,
+		Params: []string{path},
+		Attrs:  attrs,
+	})
+}
+
+func (l *LocalOperator) SetQuota(ctx context.Context, path string, quota *pb.Quota) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:   pb.Instruction_QUOTA,
+		Params: []string{path},
+		Quota:  quota,
+	})
+}
+
+func (l *LocalOperator) SetACL(ctx context.Context, prefix string, acl *pb.Acl) error {
+	return l.send(ctx, &pb.Instruction{
+		Code:   pb.Instruction_ACL,
+		Params: []string{prefix},
+		Acl:    acl,
+	})
+}
+
+func (l *LocalOperator) AddVoter(ctx context.Context, id string) error {
+	return l.members.AddVoter(id)
+}
+
+func (l *LocalOperator) AddNonVoter(ctx context.Context, id string) error {
+	return l.members.AddNonVoter(id)
+}
+
+func (l *LocalOperator) DemoteVoter(ctx context.Context, id string) error {
+	return l.members.DemoteVoter(id)
+}
+
+func (l *LocalOperator) RemovePeer(ctx context.Context, id string) error {
+	return l.members.RemovePeer(id)
+}
+
+func (l *LocalOperator) ReadIndex(ctx context.Context, verify bool) (uint64, error) {
+	return l.reader.ReadIndex(verify)
 }
 
 func (l *LocalOperator) Address() string {
 	return l.addr
 }
 
-func NewLocalOperator(r Sender, address string) *LocalOperator {
+func NewLocalOperator(r Sender, m Membership, reader ReadIndexer, address string) *LocalOperator {
 	return &LocalOperator{
-		sender: r,
-		addr:   address,
+		sender:  r,
+		members: m,
+		reader:  reader,
+		addr:    address,
 	}
 }
 
-func (l *LocalOperator) operation(code pb.Instruction_Code, nodeData []byte, params ...string) error {
+func (l *LocalOperator) operation(ctx context.Context, code pb.Instruction_Code, nodeData []byte, params ...string) error {
 	op := &pb.Instruction{
 		Code:   code,
 		Params: params,
 		Node:   nodeData,
 	}
-	return l.sender.Send(op)
+	return l.send(ctx, op)
+}
+
+func (l *LocalOperator) send(ctx context.Context, ins *pb.Instruction) error {
+	stamp(ctx, ins)
+	return l.sender.Send(ins)
 }
 
 type RemoteOperator struct {
-	client RemoteExecuteClient
-	addr   string
+	client  RemoteExecuteClient
+	members MembershipClient
+	reader  ReaderClient
+	addr    string
 }
 
-func (r *RemoteOperator) Cp(ctx context.Context, dir, path string, nodeData []byte) error {
-	_, err := r.client.Execute(ctx, &pb.Instruction{
+func (r *RemoteOperator) Cp(ctx context.Context, dir, path string, nodeData []byte, flags *pb.Flags) error {
+	return r.execute(ctx, &pb.Instruction{
 		Code:   pb.Instruction_CP,
 		Params: []string{dir, path},
 		Node:   nodeData,
+		Flags:  flags,
 	})
-	return err
 }
 
-func (r *RemoteOperator) Mv(ctx context.Context, dir, path string) error {
-	_, err := r.client.Execute(ctx, &pb.Instruction{
+func (r *RemoteOperator) Mv(ctx context.Context, dir, path string, flags *pb.Flags) error {
+	return r.execute(ctx, &pb.Instruction{
 		Code:   pb.Instruction_MV,
 		Params: []string{dir, path},
+		Flags:  flags,
 	})
-	return err
 }
 
-func (r *RemoteOperator) Rm(ctx context.Context, path string) error {
-	_, err := r.client.Execute(ctx, &pb.Instruction{
+func (r *RemoteOperator) Rm(ctx context.Context, path string, flags *pb.Flags) error {
+	return r.execute(ctx, &pb.Instruction{
 		Code:   pb.Instruction_RM,
 		Params: []string{path},
+		Flags:  flags,
 	})
-	return err
 }
 
 func (r *RemoteOperator) MkDir(ctx context.Context, path string) error {
-	_, err := r.client.Execute(ctx, &pb.Instruction{
+	return r.execute(ctx, &pb.Instruction{
 		Code:   pb.Instruction_MKDIR,
 		Params: []string{path},
 	})
+}
+
+func (r *RemoteOperator) Transact(ctx context.Context, tx *pb.Transaction) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code: pb.Instruction_TX,
+		Tx:   tx,
+	})
+}
+
+func (r *RemoteOperator) Restore(ctx context.Context, path, c string, nodeData []byte, attrs map[string]*pb.Attributes) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:     pb.Instruction_RESTORE,
+		Params:   []string{path, c},
+		Node:     nodeData,
+		Restored: attrs,
+	})
+}
+
+func (r *RemoteOperator) Tag(ctx context.Context, name, created string) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:   pb.Instruction_TAG,
+		Params: []string{name, created},
+	})
+}
+
+func (r *RemoteOperator) Untag(ctx context.Context, name string) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:   pb.Instruction_UNTAG,
+		Params: []string{name},
+	})
+}
+
+func (r *RemoteOperator) Symlink(ctx context.Context, path, target string) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:   pb.Instruction_SYMLINK,
+		Params: []string{path, target},
+	})
+}
+
+func (r *RemoteOperator) SetAttr(ctx context.Context, path string, attrs *pb.Attributes) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:   pb.Instruction_SETATTR,
+		Params: []string{path},
+		Attrs:  attrs,
+	})
+}
+
+func (r *RemoteOperator) SetQuota(ctx context.Context, path string, quota *pb.Quota) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:   pb.Instruction_QUOTA,
+		Params: []string{path},
+		Quota:  quota,
+	})
+}
+
+func (r *RemoteOperator) SetACL(ctx context.Context, prefix string, acl *pb.Acl) error {
+	return r.execute(ctx, &pb.Instruction{
+		Code:   pb.Instruction_ACL,
+		Params: []string{prefix},
+		Acl:    acl,
+	})
+}
+
+func (r *RemoteOperator) AddVoter(ctx context.Context, id string) error {
+	_, err := r.members.AddVoter(ctx, &pb.Peer{Id: id})
 	return err
 }
 
+func (r *RemoteOperator) AddNonVoter(ctx context.Context, id string) error {
+	_, err := r.members.AddNonVoter(ctx, &pb.Peer{Id: id})
+	return err
+}
+
+func (r *RemoteOperator) DemoteVoter(ctx context.Context, id string) error {
+	_, err := r.members.DemoteVoter(ctx, &pb.Peer{Id: id})
+	return err
+}
+
+func (r *RemoteOperator) RemovePeer(ctx context.Context, id string) error {
+	_, err := r.members.RemovePeer(ctx, &pb.Peer{Id: id})
+	return err
+}
+
+func (r *RemoteOperator) ReadIndex(ctx context.Context, verify bool) (uint64, error) {
+	res, err := r.reader.ReadIndex(ctx, &pb.ReadIndexRequest{Verify: verify})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetIndex(), nil
+}
+
 func (r *RemoteOperator) Address() string {
 	return r.addr
 }
 
+func (r *RemoteOperator) execute(ctx context.Context, ins *pb.Instruction) error {
+	stamp(ctx, ins)
+	_, err := r.client.Execute(ctx, ins)
+	return err
+}
+
+type originKey struct{}
+
+// OriginKey is where the origin is kept in contexts that only look values up
+// by string, such as gin's.
+const OriginKey = "icetrays.origin"
+
+// WithOrigin attaches the request an instruction comes from to ctx.
+func WithOrigin(ctx context.Context, origin *pb.Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// stamp records on ins the caller authenticated by the API, whom the leader
+// checks against its ACLs, and the request it comes from for the audit log.
+func stamp(ctx context.Context, ins *pb.Instruction) {
+	id, _ := auth.FromContext(ctx)
+	ins.Principal = id.Name
+	origin, ok := ctx.Value(originKey{}).(*pb.Origin)
+	if !ok {
+		origin, _ = ctx.Value(OriginKey).(*pb.Origin)
+	}
+	ins.Origin = &pb.Origin{
+		Client:    origin.GetClient(),
+		RequestId: origin.GetRequestId(),
+		Time:      origin.GetTime(),
+	}
+	if ins.Origin.Time == 0 {
+		ins.Origin.Time = time.Now().UnixNano()
+	}
+}
+
 func NewRemoteOperator(conn grpc.ClientConnInterface, addr string) *RemoteOperator {
 	return &RemoteOperator{
-		client: NewRemoteExecuteClient(conn),
-		addr:   addr,
+		client:  NewRemoteExecuteClient(conn),
+		members: NewMembershipClient(conn),
+		reader:  NewReaderClient(conn),
+		addr:    addr,
 	}
 }
 
@@ -117,7 +350,7 @@ type FsOpServer struct {
 
 func (f FsOpServer) Execute(ctx context.Context, instruction *pb.Instruction) (*pb.Empty, error) {
 	err := f.operator.Send(instruction)
-	return &pb.Empty{}, err
+	return &pb.Empty{}, statusError(err)
 }
 
 func (f FsOpServer) mustEmbedUnimplementedRemoteExecuteServer() {
@@ -23,27 +23,51 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 type Instruction_Code int32
 
 const (
-	Instruction_CP    Instruction_Code = 0
-	Instruction_MV    Instruction_Code = 1
-	Instruction_RM    Instruction_Code = 2
-	Instruction_MKDIR Instruction_Code = 3
-	Instruction_Ls    Instruction_Code = 4
+	Instruction_CP      Instruction_Code = 0
+	Instruction_MV      Instruction_Code = 1
+	Instruction_RM      Instruction_Code = 2
+	Instruction_MKDIR   Instruction_Code = 3
+	Instruction_Ls      Instruction_Code = 4
+	Instruction_TX      Instruction_Code = 5
+	Instruction_RESTORE Instruction_Code = 6
+	Instruction_TAG     Instruction_Code = 7
+	Instruction_UNTAG   Instruction_Code = 8
+	Instruction_SYMLINK Instruction_Code = 9
+	Instruction_SETATTR Instruction_Code = 10
+	Instruction_QUOTA   Instruction_Code = 11
+	Instruction_ACL     Instruction_Code = 12
 )
 
 var Instruction_Code_name = map[int32]string{
-	0: "CP",
-	1: "MV",
-	2: "RM",
-	3: "MKDIR",
-	4: "Ls",
+	0:  "CP",
+	1:  "MV",
+	2:  "RM",
+	3:  "MKDIR",
+	4:  "Ls",
+	5:  "TX",
+	6:  "RESTORE",
+	7:  "TAG",
+	8:  "UNTAG",
+	9:  "SYMLINK",
+	10: "SETATTR",
+	11: "QUOTA",
+	12: "ACL",
 }
 
 var Instruction_Code_value = map[string]int32{
-	"CP":    0,
-	"MV":    1,
-	"RM":    2,
-	"MKDIR": 3,
-	"Ls":    4,
+	"CP":      0,
+	"MV":      1,
+	"RM":      2,
+	"MKDIR":   3,
+	"Ls":      4,
+	"TX":      5,
+	"RESTORE": 6,
+	"TAG":     7,
+	"UNTAG":   8,
+	"SYMLINK": 9,
+	"SETATTR": 10,
+	"QUOTA":   11,
+	"ACL":     12,
 }
 
 func (x Instruction_Code) String() string {
@@ -51,7 +75,35 @@ func (x Instruction_Code) String() string {
 }
 
 func (Instruction_Code) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_0e1a8c64c0f1b0bd, []int{2, 0}
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{8, 0}
+}
+
+type Precondition_Kind int32
+
+const (
+	Precondition_EXISTS     Precondition_Kind = 0
+	Precondition_ABSENT     Precondition_Kind = 1
+	Precondition_CID_EQUALS Precondition_Kind = 2
+)
+
+var Precondition_Kind_name = map[int32]string{
+	0: "EXISTS",
+	1: "ABSENT",
+	2: "CID_EQUALS",
+}
+
+var Precondition_Kind_value = map[string]int32{
+	"EXISTS":     0,
+	"ABSENT":     1,
+	"CID_EQUALS": 2,
+}
+
+func (x Precondition_Kind) String() string {
+	return proto.EnumName(Precondition_Kind_name, int32(x))
+}
+
+func (Precondition_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{15, 0}
 }
 
 type Ctx struct {
@@ -130,20 +182,304 @@ func (m *Empty) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
+type Peer struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Peer) Reset()         { *m = Peer{} }
+func (m *Peer) String() string { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()    {}
+func (*Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{2}
+}
+func (m *Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Peer.Unmarshal(m, b)
+}
+func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+}
+func (m *Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Peer.Merge(m, src)
+}
+func (m *Peer) XXX_Size() int {
+	return xxx_messageInfo_Peer.Size(m)
+}
+func (m *Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Peer proto.InternalMessageInfo
+
+func (m *Peer) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type SubscribeRequest struct {
+	FromIndex            uint64   `protobuf:"varint,1,opt,name=from_index,json=fromIndex,proto3" json:"from_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{3}
+}
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+func (m *SubscribeRequest) GetFromIndex() uint64 {
+	if m != nil {
+		return m.FromIndex
+	}
+	return 0
+}
+
+type Event struct {
+	Index                uint64    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Term                 uint64    `protobuf:"varint,2,opt,name=term,proto3" json:"term,omitempty"`
+	Pre                  string    `protobuf:"bytes,3,opt,name=pre,proto3" json:"pre,omitempty"`
+	Next                 string    `protobuf:"bytes,4,opt,name=next,proto3" json:"next,omitempty"`
+	Changes              []*Change `protobuf:"bytes,5,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{4}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Event) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *Event) GetPre() string {
+	if m != nil {
+		return m.Pre
+	}
+	return ""
+}
+
+func (m *Event) GetNext() string {
+	if m != nil {
+		return m.Next
+	}
+	return ""
+}
+
+func (m *Event) GetChanges() []*Change {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type Change struct {
+	Op                   string   `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Src                  string   `protobuf:"bytes,3,opt,name=src,proto3" json:"src,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Change) Reset()         { *m = Change{} }
+func (m *Change) String() string { return proto.CompactTextString(m) }
+func (*Change) ProtoMessage()    {}
+func (*Change) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{5}
+}
+func (m *Change) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Change.Unmarshal(m, b)
+}
+func (m *Change) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Change.Marshal(b, m, deterministic)
+}
+func (m *Change) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Change.Merge(m, src)
+}
+func (m *Change) XXX_Size() int {
+	return xxx_messageInfo_Change.Size(m)
+}
+func (m *Change) XXX_DiscardUnknown() {
+	xxx_messageInfo_Change.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Change proto.InternalMessageInfo
+
+func (m *Change) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *Change) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Change) GetSrc() string {
+	if m != nil {
+		return m.Src
+	}
+	return ""
+}
+
+type ReadIndexRequest struct {
+	Verify               bool     `protobuf:"varint,1,opt,name=verify,proto3" json:"verify,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadIndexRequest) Reset()         { *m = ReadIndexRequest{} }
+func (m *ReadIndexRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadIndexRequest) ProtoMessage()    {}
+func (*ReadIndexRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{6}
+}
+func (m *ReadIndexRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadIndexRequest.Unmarshal(m, b)
+}
+func (m *ReadIndexRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadIndexRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadIndexRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadIndexRequest.Merge(m, src)
+}
+func (m *ReadIndexRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadIndexRequest.Size(m)
+}
+func (m *ReadIndexRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadIndexRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadIndexRequest proto.InternalMessageInfo
+
+func (m *ReadIndexRequest) GetVerify() bool {
+	if m != nil {
+		return m.Verify
+	}
+	return false
+}
+
+type ReadIndexResponse struct {
+	Index                uint64   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadIndexResponse) Reset()         { *m = ReadIndexResponse{} }
+func (m *ReadIndexResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadIndexResponse) ProtoMessage()    {}
+func (*ReadIndexResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{7}
+}
+func (m *ReadIndexResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadIndexResponse.Unmarshal(m, b)
+}
+func (m *ReadIndexResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadIndexResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadIndexResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadIndexResponse.Merge(m, src)
+}
+func (m *ReadIndexResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadIndexResponse.Size(m)
+}
+func (m *ReadIndexResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadIndexResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadIndexResponse proto.InternalMessageInfo
+
+func (m *ReadIndexResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
 type Instruction struct {
-	Code                 Instruction_Code `protobuf:"varint,1,opt,name=code,proto3,enum=pb.Instruction_Code" json:"code,omitempty"`
-	Params               []string         `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"`
-	Node                 []byte           `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Code                 Instruction_Code       `protobuf:"varint,1,opt,name=code,proto3,enum=pb.Instruction_Code" json:"code,omitempty"`
+	Params               []string               `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"`
+	Node                 []byte                 `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	Tx                   *Transaction           `protobuf:"bytes,4,opt,name=tx,proto3" json:"tx,omitempty"`
+	Flags                *Flags                 `protobuf:"bytes,5,opt,name=flags,proto3" json:"flags,omitempty"`
+	Attrs                *Attributes            `protobuf:"bytes,6,opt,name=attrs,proto3" json:"attrs,omitempty"`
+	Quota                *Quota                 `protobuf:"bytes,7,opt,name=quota,proto3" json:"quota,omitempty"`
+	Acl                  *Acl                   `protobuf:"bytes,8,opt,name=acl,proto3" json:"acl,omitempty"`
+	Principal            string                 `protobuf:"bytes,9,opt,name=principal,proto3" json:"principal,omitempty"`
+	Origin               *Origin                `protobuf:"bytes,10,opt,name=origin,proto3" json:"origin,omitempty"`
+	Restored             map[string]*Attributes `protobuf:"bytes,11,rep,name=restored,proto3" json:"restored,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
 }
 
 func (m *Instruction) Reset()         { *m = Instruction{} }
 func (m *Instruction) String() string { return proto.CompactTextString(m) }
 func (*Instruction) ProtoMessage()    {}
 func (*Instruction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0e1a8c64c0f1b0bd, []int{2}
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{8}
 }
 func (m *Instruction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Instruction.Unmarshal(m, b)
@@ -184,6 +520,416 @@ func (m *Instruction) GetNode() []byte {
 	return nil
 }
 
+func (m *Instruction) GetTx() *Transaction {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *Instruction) GetFlags() *Flags {
+	if m != nil {
+		return m.Flags
+	}
+	return nil
+}
+
+func (m *Instruction) GetAttrs() *Attributes {
+	if m != nil {
+		return m.Attrs
+	}
+	return nil
+}
+
+func (m *Instruction) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+func (m *Instruction) GetAcl() *Acl {
+	if m != nil {
+		return m.Acl
+	}
+	return nil
+}
+
+func (m *Instruction) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *Instruction) GetOrigin() *Origin {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+func (m *Instruction) GetRestored() map[string]*Attributes {
+	if m != nil {
+		return m.Restored
+	}
+	return nil
+}
+
+type Origin struct {
+	Client               string   `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+	RequestId            string   `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Time                 int64    `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Origin) Reset()         { *m = Origin{} }
+func (m *Origin) String() string { return proto.CompactTextString(m) }
+func (*Origin) ProtoMessage()    {}
+func (*Origin) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{9}
+}
+func (m *Origin) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Origin.Unmarshal(m, b)
+}
+func (m *Origin) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Origin.Marshal(b, m, deterministic)
+}
+func (m *Origin) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Origin.Merge(m, src)
+}
+func (m *Origin) XXX_Size() int {
+	return xxx_messageInfo_Origin.Size(m)
+}
+func (m *Origin) XXX_DiscardUnknown() {
+	xxx_messageInfo_Origin.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Origin proto.InternalMessageInfo
+
+func (m *Origin) GetClient() string {
+	if m != nil {
+		return m.Client
+	}
+	return ""
+}
+
+func (m *Origin) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *Origin) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+type Acl struct {
+	Principal            string   `protobuf:"bytes,1,opt,name=principal,proto3" json:"principal,omitempty"`
+	Ops                  []string `protobuf:"bytes,2,rep,name=ops,proto3" json:"ops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Acl) Reset()         { *m = Acl{} }
+func (m *Acl) String() string { return proto.CompactTextString(m) }
+func (*Acl) ProtoMessage()    {}
+func (*Acl) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{10}
+}
+func (m *Acl) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Acl.Unmarshal(m, b)
+}
+func (m *Acl) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Acl.Marshal(b, m, deterministic)
+}
+func (m *Acl) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Acl.Merge(m, src)
+}
+func (m *Acl) XXX_Size() int {
+	return xxx_messageInfo_Acl.Size(m)
+}
+func (m *Acl) XXX_DiscardUnknown() {
+	xxx_messageInfo_Acl.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Acl proto.InternalMessageInfo
+
+func (m *Acl) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *Acl) GetOps() []string {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+type Quota struct {
+	MaxBytes             uint64   `protobuf:"varint,1,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	MaxEntries           uint64   `protobuf:"varint,2,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Quota) Reset()         { *m = Quota{} }
+func (m *Quota) String() string { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()    {}
+func (*Quota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{11}
+}
+func (m *Quota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Quota.Unmarshal(m, b)
+}
+func (m *Quota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Quota.Marshal(b, m, deterministic)
+}
+func (m *Quota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quota.Merge(m, src)
+}
+func (m *Quota) XXX_Size() int {
+	return xxx_messageInfo_Quota.Size(m)
+}
+func (m *Quota) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quota proto.InternalMessageInfo
+
+func (m *Quota) GetMaxBytes() uint64 {
+	if m != nil {
+		return m.MaxBytes
+	}
+	return 0
+}
+
+func (m *Quota) GetMaxEntries() uint64 {
+	if m != nil {
+		return m.MaxEntries
+	}
+	return 0
+}
+
+type Attributes struct {
+	Mode                 uint32            `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Mtime                int64             `protobuf:"varint,2,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Attributes) Reset()         { *m = Attributes{} }
+func (m *Attributes) String() string { return proto.CompactTextString(m) }
+func (*Attributes) ProtoMessage()    {}
+func (*Attributes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{12}
+}
+func (m *Attributes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Attributes.Unmarshal(m, b)
+}
+func (m *Attributes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Attributes.Marshal(b, m, deterministic)
+}
+func (m *Attributes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Attributes.Merge(m, src)
+}
+func (m *Attributes) XXX_Size() int {
+	return xxx_messageInfo_Attributes.Size(m)
+}
+func (m *Attributes) XXX_DiscardUnknown() {
+	xxx_messageInfo_Attributes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Attributes proto.InternalMessageInfo
+
+func (m *Attributes) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *Attributes) GetMtime() int64 {
+	if m != nil {
+		return m.Mtime
+	}
+	return 0
+}
+
+func (m *Attributes) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type Flags struct {
+	Recursive            bool     `protobuf:"varint,1,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	Force                bool     `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	Parents              bool     `protobuf:"varint,3,opt,name=parents,proto3" json:"parents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Flags) Reset()         { *m = Flags{} }
+func (m *Flags) String() string { return proto.CompactTextString(m) }
+func (*Flags) ProtoMessage()    {}
+func (*Flags) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{13}
+}
+func (m *Flags) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Flags.Unmarshal(m, b)
+}
+func (m *Flags) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Flags.Marshal(b, m, deterministic)
+}
+func (m *Flags) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Flags.Merge(m, src)
+}
+func (m *Flags) XXX_Size() int {
+	return xxx_messageInfo_Flags.Size(m)
+}
+func (m *Flags) XXX_DiscardUnknown() {
+	xxx_messageInfo_Flags.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Flags proto.InternalMessageInfo
+
+func (m *Flags) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+func (m *Flags) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *Flags) GetParents() bool {
+	if m != nil {
+		return m.Parents
+	}
+	return false
+}
+
+type Transaction struct {
+	Preconditions        []*Precondition `protobuf:"bytes,1,rep,name=preconditions,proto3" json:"preconditions,omitempty"`
+	Instructions         []*Instruction  `protobuf:"bytes,2,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{14}
+}
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetPreconditions() []*Precondition {
+	if m != nil {
+		return m.Preconditions
+	}
+	return nil
+}
+
+func (m *Transaction) GetInstructions() []*Instruction {
+	if m != nil {
+		return m.Instructions
+	}
+	return nil
+}
+
+type Precondition struct {
+	Kind                 Precondition_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=pb.Precondition_Kind" json:"kind,omitempty"`
+	Path                 string            `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Cid                  string            `protobuf:"bytes,3,opt,name=cid,proto3" json:"cid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Precondition) Reset()         { *m = Precondition{} }
+func (m *Precondition) String() string { return proto.CompactTextString(m) }
+func (*Precondition) ProtoMessage()    {}
+func (*Precondition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{15}
+}
+func (m *Precondition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Precondition.Unmarshal(m, b)
+}
+func (m *Precondition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Precondition.Marshal(b, m, deterministic)
+}
+func (m *Precondition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Precondition.Merge(m, src)
+}
+func (m *Precondition) XXX_Size() int {
+	return xxx_messageInfo_Precondition.Size(m)
+}
+func (m *Precondition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Precondition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Precondition proto.InternalMessageInfo
+
+func (m *Precondition) GetKind() Precondition_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return Precondition_EXISTS
+}
+
+func (m *Precondition) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Precondition) GetCid() string {
+	if m != nil {
+		return m.Cid
+	}
+	return ""
+}
+
 type Instructions struct {
 	Instruction          []*Instruction `protobuf:"bytes,1,rep,name=instruction,proto3" json:"instruction,omitempty"`
 	Ctx                  *Ctx           `protobuf:"bytes,2,opt,name=ctx,proto3" json:"ctx,omitempty"`
@@ -196,7 +942,7 @@ func (m *Instructions) Reset()         { *m = Instructions{} }
 func (m *Instructions) String() string { return proto.CompactTextString(m) }
 func (*Instructions) ProtoMessage()    {}
 func (*Instructions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0e1a8c64c0f1b0bd, []int{3}
+	return fileDescriptor_0e1a8c64c0f1b0bd, []int{16}
 }
 func (m *Instructions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Instructions.Unmarshal(m, b)
@@ -232,32 +978,100 @@ func (m *Instructions) GetCtx() *Ctx {
 
 func init() {
 	proto.RegisterEnum("pb.Instruction_Code", Instruction_Code_name, Instruction_Code_value)
+	proto.RegisterEnum("pb.Precondition_Kind", Precondition_Kind_name, Precondition_Kind_value)
 	proto.RegisterType((*Ctx)(nil), "pb.Ctx")
 	proto.RegisterType((*Empty)(nil), "pb.Empty")
+	proto.RegisterType((*Peer)(nil), "pb.Peer")
+	proto.RegisterType((*SubscribeRequest)(nil), "pb.SubscribeRequest")
+	proto.RegisterType((*Event)(nil), "pb.Event")
+	proto.RegisterType((*Change)(nil), "pb.Change")
+	proto.RegisterType((*ReadIndexRequest)(nil), "pb.ReadIndexRequest")
+	proto.RegisterType((*ReadIndexResponse)(nil), "pb.ReadIndexResponse")
 	proto.RegisterType((*Instruction)(nil), "pb.Instruction")
+	proto.RegisterMapType((map[string]*Attributes)(nil), "pb.Instruction.RestoredEntry")
+	proto.RegisterType((*Origin)(nil), "pb.Origin")
+	proto.RegisterType((*Acl)(nil), "pb.Acl")
+	proto.RegisterType((*Quota)(nil), "pb.Quota")
+	proto.RegisterType((*Attributes)(nil), "pb.Attributes")
+	proto.RegisterMapType((map[string]string)(nil), "pb.Attributes.MetadataEntry")
+	proto.RegisterType((*Flags)(nil), "pb.Flags")
+	proto.RegisterType((*Transaction)(nil), "pb.Transaction")
+	proto.RegisterType((*Precondition)(nil), "pb.Precondition")
 	proto.RegisterType((*Instructions)(nil), "pb.Instructions")
 }
 
 func init() { proto.RegisterFile("consensus/pb/fs.proto", fileDescriptor_0e1a8c64c0f1b0bd) }
 
 var fileDescriptor_0e1a8c64c0f1b0bd = []byte{
-	// 288 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0x41, 0x4b, 0xf4, 0x30,
-	0x10, 0x86, 0x9b, 0xa4, 0xdb, 0xd2, 0xe9, 0x7e, 0x9f, 0x21, 0xa8, 0x54, 0x4f, 0xa5, 0x17, 0x0b,
-	0x42, 0x97, 0xad, 0x17, 0xcf, 0xd6, 0x3d, 0x2c, 0x5a, 0x90, 0x1c, 0x3c, 0x88, 0x97, 0x6d, 0x37,
-	0xc2, 0x1e, 0xda, 0x84, 0x26, 0x85, 0xfa, 0x47, 0xfc, 0xbd, 0x92, 0xac, 0x62, 0xd9, 0xd3, 0xbc,
-	0xf3, 0xe4, 0x9d, 0x99, 0xcc, 0xc0, 0x45, 0x2b, 0x7b, 0x2d, 0x7a, 0x3d, 0xea, 0x95, 0x6a, 0x56,
-	0x1f, 0xba, 0x50, 0x83, 0x34, 0x92, 0x61, 0xd5, 0x64, 0xb7, 0x40, 0x2a, 0x33, 0x31, 0x0a, 0x44,
-	0x0d, 0x22, 0x41, 0x29, 0xca, 0x23, 0x6e, 0x25, 0x63, 0xe0, 0xf7, 0x62, 0x32, 0x09, 0x76, 0xc8,
-	0xe9, 0x2c, 0x84, 0xc5, 0xa6, 0x53, 0xe6, 0x33, 0xfb, 0x42, 0x10, 0x6f, 0x7b, 0x6d, 0x86, 0xb1,
-	0x35, 0x07, 0xd9, 0xb3, 0x1c, 0xfc, 0x56, 0xee, 0x8f, 0xf5, 0xff, 0xcb, 0xf3, 0x42, 0x35, 0xc5,
-	0xec, 0xb9, 0xa8, 0xe4, 0x5e, 0x70, 0xe7, 0x60, 0x97, 0x10, 0xa8, 0xdd, 0xb0, 0xeb, 0x74, 0x82,
-	0x53, 0x92, 0x47, 0xfc, 0x27, 0x73, 0xe3, 0x6c, 0x07, 0x92, 0xa2, 0x7c, 0xc9, 0x9d, 0xce, 0xd6,
-	0xe0, 0xdb, 0x4a, 0x16, 0x00, 0xae, 0x5e, 0xa8, 0x67, 0x63, 0xfd, 0x4a, 0x91, 0x8d, 0xbc, 0xa6,
-	0x98, 0x45, 0xb0, 0xa8, 0x9f, 0x1e, 0xb7, 0x9c, 0x12, 0x8b, 0x9e, 0x35, 0xf5, 0xb3, 0x77, 0x58,
-	0xce, 0x06, 0x6b, 0xb6, 0x86, 0xf8, 0xf0, 0x97, 0x27, 0x28, 0x25, 0x79, 0x5c, 0x9e, 0x9d, 0xfc,
-	0x8f, 0xcf, 0x3d, 0xec, 0x0a, 0x48, 0x6b, 0x26, 0xb7, 0x77, 0x5c, 0x86, 0xd6, 0x5a, 0x99, 0x89,
-	0x5b, 0x56, 0xde, 0xc3, 0x3f, 0x2e, 0x3a, 0x69, 0xc4, 0x66, 0x12, 0xed, 0x68, 0x04, 0xbb, 0x81,
-	0xf0, 0x57, 0x9e, 0x36, 0xbd, 0x8e, 0x2c, 0x38, 0x9e, 0xcb, 0x7b, 0xc0, 0x6f, 0x5e, 0x13, 0xb8,
-	0xab, 0xdf, 0x7d, 0x07, 0x00, 0x00, 0xff, 0xff, 0xa8, 0x5b, 0xfe, 0x59, 0x8e, 0x01, 0x00, 0x00,
+	// 1117 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x56, 0x6d, 0x6f, 0xe3, 0x44,
+	0x10, 0x8e, 0xed, 0xbc, 0x79, 0xd2, 0x16, 0xb3, 0xba, 0x9e, 0x4c, 0xb9, 0x53, 0x8b, 0xa9, 0x44,
+	0x0f, 0xa4, 0xdc, 0x5d, 0x4e, 0xa0, 0x72, 0x48, 0x27, 0xa5, 0xa9, 0x0f, 0x45, 0x6d, 0xfa, 0xb2,
+	0x49, 0x4f, 0x07, 0x42, 0xaa, 0x1c, 0x7b, 0xdb, 0x5a, 0x8d, 0x5f, 0x6e, 0x77, 0x53, 0x25, 0x48,
+	0x7c, 0xe7, 0x1b, 0x7f, 0x81, 0x3f, 0xc0, 0x3f, 0xe2, 0xc7, 0xa0, 0x59, 0xdb, 0x8d, 0x13, 0x7a,
+	0xf0, 0xc9, 0x33, 0xcf, 0x3c, 0x1e, 0xcf, 0xcc, 0xce, 0xcc, 0x1a, 0x36, 0xfd, 0x24, 0x16, 0x2c,
+	0x16, 0x53, 0xf1, 0x3c, 0x1d, 0x3f, 0xbf, 0x12, 0xed, 0x94, 0x27, 0x32, 0x21, 0x7a, 0x3a, 0x76,
+	0xbe, 0x01, 0xa3, 0x27, 0x67, 0xc4, 0x02, 0x23, 0xe5, 0xcc, 0xd6, 0x76, 0xb4, 0x3d, 0x93, 0xa2,
+	0x48, 0x08, 0x54, 0x63, 0x36, 0x93, 0xb6, 0xae, 0x20, 0x25, 0x3b, 0x0d, 0xa8, 0xb9, 0x51, 0x2a,
+	0xe7, 0xce, 0x63, 0xa8, 0x9e, 0x31, 0xc6, 0xc9, 0x06, 0xe8, 0x61, 0x90, 0xbf, 0xa5, 0x87, 0x81,
+	0xf3, 0x12, 0xac, 0xe1, 0x74, 0x2c, 0x7c, 0x1e, 0x8e, 0x19, 0x65, 0x1f, 0xa6, 0x4c, 0x48, 0xf2,
+	0x14, 0xe0, 0x8a, 0x27, 0xd1, 0x65, 0x18, 0x07, 0x6c, 0xa6, 0xb8, 0x55, 0x6a, 0x22, 0xd2, 0x47,
+	0xc0, 0xf9, 0x0d, 0x6a, 0xee, 0x1d, 0x8b, 0x25, 0x79, 0x04, 0xb5, 0x32, 0x25, 0x53, 0x30, 0x0c,
+	0xc9, 0x78, 0xa4, 0xc2, 0xa8, 0x52, 0x25, 0x17, 0xc1, 0x1a, 0xff, 0x0e, 0xb6, 0xba, 0x08, 0x96,
+	0xec, 0x42, 0xc3, 0xbf, 0xf1, 0xe2, 0x6b, 0x26, 0xec, 0xda, 0x8e, 0xb1, 0xd7, 0xea, 0x40, 0x3b,
+	0x1d, 0xb7, 0x7b, 0x0a, 0xa2, 0x85, 0xc9, 0x79, 0x03, 0xf5, 0x0c, 0xc2, 0x5c, 0x92, 0xb4, 0xc8,
+	0x25, 0x49, 0xd1, 0x67, 0xea, 0xc9, 0x9b, 0xa2, 0x00, 0x28, 0xe3, 0x97, 0x05, 0xf7, 0x8b, 0x2f,
+	0x0b, 0xee, 0x3b, 0x5f, 0x83, 0x45, 0x99, 0x17, 0xa8, 0x5c, 0x8a, 0x8c, 0x1f, 0x43, 0xfd, 0x8e,
+	0xf1, 0xf0, 0x6a, 0xae, 0xbc, 0x35, 0x69, 0xae, 0x39, 0xcf, 0xe0, 0xd3, 0x12, 0x57, 0xa4, 0x78,
+	0x28, 0x0f, 0xa7, 0xed, 0xfc, 0x5d, 0x85, 0x56, 0x3f, 0x16, 0x92, 0x4f, 0x7d, 0x19, 0x26, 0x31,
+	0xd9, 0x83, 0xaa, 0x9f, 0x04, 0xd9, 0x01, 0x6d, 0x74, 0x1e, 0x61, 0x26, 0x25, 0x73, 0xbb, 0x97,
+	0x04, 0x8c, 0x2a, 0x06, 0x7e, 0x3c, 0xf5, 0xb8, 0x17, 0x09, 0x5b, 0xdf, 0x31, 0xf6, 0x4c, 0x9a,
+	0x6b, 0xaa, 0x44, 0xe8, 0x01, 0x63, 0x5f, 0xa3, 0x4a, 0x26, 0xdb, 0xa0, 0xcb, 0x99, 0x2a, 0x5a,
+	0xab, 0xf3, 0x09, 0xfa, 0x1c, 0x71, 0x2f, 0x16, 0x9e, 0xf2, 0x49, 0x75, 0x39, 0x23, 0xdb, 0x50,
+	0xbb, 0x9a, 0x78, 0xd7, 0x58, 0x41, 0xe4, 0x98, 0xc8, 0x79, 0x8b, 0x00, 0xcd, 0x70, 0xb2, 0x0b,
+	0x35, 0x4f, 0x4a, 0x2e, 0xec, 0xba, 0x22, 0x6c, 0x20, 0xa1, 0x2b, 0x25, 0x0f, 0xc7, 0x53, 0xc9,
+	0x04, 0xcd, 0x8c, 0xe8, 0xe6, 0xc3, 0x34, 0x91, 0x9e, 0xdd, 0x58, 0xb8, 0x39, 0x47, 0x80, 0x66,
+	0x38, 0xf9, 0x0c, 0x0c, 0xcf, 0x9f, 0xd8, 0x4d, 0x65, 0x6e, 0x28, 0x27, 0xfe, 0x84, 0x22, 0x46,
+	0x9e, 0x80, 0x99, 0xf2, 0x30, 0xf6, 0xc3, 0xd4, 0x9b, 0xd8, 0xa6, 0x2a, 0xfc, 0x02, 0x20, 0x0e,
+	0xd4, 0x13, 0x1e, 0x5e, 0x87, 0xb1, 0x0d, 0x3b, 0x5a, 0x71, 0xc6, 0xa7, 0x0a, 0xa1, 0xb9, 0x85,
+	0x7c, 0x0f, 0x4d, 0xce, 0x84, 0x4c, 0x38, 0x0b, 0xec, 0x96, 0xea, 0x84, 0xa7, 0xab, 0xf5, 0xa3,
+	0xb9, 0xdd, 0x8d, 0x25, 0x9f, 0xd3, 0x7b, 0xfa, 0xd6, 0x11, 0xac, 0x2f, 0x99, 0xb0, 0x01, 0x6e,
+	0xd9, 0xbc, 0x98, 0x93, 0x5b, 0x36, 0xc7, 0x0a, 0xdc, 0x79, 0x93, 0x29, 0xb3, 0xf5, 0x87, 0x2b,
+	0xa0, 0x8c, 0xaf, 0xf5, 0x7d, 0xcd, 0xf9, 0x5d, 0x83, 0x2a, 0x1e, 0x14, 0xa9, 0x83, 0xde, 0x3b,
+	0xb3, 0x2a, 0xf8, 0x1c, 0xbc, 0xb3, 0x34, 0x7c, 0xd2, 0x81, 0xa5, 0x13, 0x13, 0x6a, 0x83, 0xa3,
+	0xc3, 0x3e, 0xb5, 0x0c, 0x84, 0x8e, 0x85, 0x55, 0xc5, 0xe7, 0xe8, 0xbd, 0x55, 0x23, 0x2d, 0x68,
+	0x50, 0x77, 0x38, 0x3a, 0xa5, 0xae, 0x55, 0x27, 0x0d, 0x30, 0x46, 0xdd, 0x1f, 0xad, 0x06, 0xbe,
+	0x70, 0x71, 0x82, 0x62, 0x13, 0x09, 0xc3, 0x9f, 0x06, 0xc7, 0xfd, 0x93, 0x23, 0xcb, 0x54, 0x8a,
+	0x3b, 0xea, 0x8e, 0x46, 0xd4, 0x02, 0x24, 0x9d, 0x5f, 0x9c, 0x8e, 0xba, 0x56, 0x0b, 0x5f, 0xec,
+	0xf6, 0x8e, 0xad, 0x35, 0x67, 0x08, 0xf5, 0xac, 0x48, 0xd8, 0x2e, 0xfe, 0x24, 0x64, 0xb1, 0xcc,
+	0x73, 0xca, 0x35, 0x9c, 0x5a, 0x9e, 0xb5, 0xf3, 0x65, 0x18, 0xe4, 0x33, 0x60, 0xe6, 0x48, 0x3f,
+	0x50, 0x63, 0x19, 0x46, 0x59, 0x37, 0x19, 0x54, 0xc9, 0xce, 0xb7, 0x60, 0x74, 0x57, 0x0f, 0x4c,
+	0x5b, 0x3d, 0x30, 0x0b, 0x8c, 0x24, 0x2d, 0x7a, 0x13, 0x45, 0xc7, 0x85, 0x9a, 0xea, 0x05, 0xf2,
+	0x39, 0x98, 0x91, 0x37, 0xbb, 0x1c, 0xcf, 0x25, 0x13, 0xf9, 0x34, 0x34, 0x23, 0x6f, 0x76, 0x80,
+	0x3a, 0xd9, 0x86, 0x16, 0x1a, 0x59, 0x2c, 0x79, 0xc8, 0x44, 0xbe, 0x0e, 0x20, 0xf2, 0x66, 0x6e,
+	0x86, 0x38, 0x7f, 0x69, 0x00, 0x8b, 0xba, 0x63, 0x80, 0x51, 0x31, 0x30, 0xeb, 0x54, 0xc9, 0x38,
+	0x6a, 0x91, 0x8a, 0x5a, 0x57, 0x51, 0x67, 0x0a, 0xd9, 0x87, 0x66, 0xc4, 0xa4, 0x17, 0x78, 0xd2,
+	0xb3, 0x0d, 0xd5, 0x1e, 0x4f, 0x96, 0xcf, 0xb0, 0x3d, 0xc8, 0xcd, 0x79, 0x77, 0x14, 0xec, 0xad,
+	0x1f, 0x60, 0x7d, 0xc9, 0xf4, 0x40, 0x77, 0x3c, 0x2a, 0x77, 0x87, 0x59, 0xee, 0x86, 0x0b, 0xa8,
+	0xa9, 0x49, 0xc2, 0x7a, 0x71, 0xe6, 0x4f, 0xb9, 0x08, 0xef, 0x58, 0xbe, 0x30, 0x16, 0x00, 0x3a,
+	0xb8, 0x4a, 0xb8, 0x9f, 0x39, 0x68, 0xd2, 0x4c, 0x21, 0x36, 0x34, 0x52, 0x8f, 0xb3, 0x58, 0x0a,
+	0x75, 0x02, 0x4d, 0x5a, 0xa8, 0xce, 0xaf, 0xd0, 0x2a, 0x0d, 0x31, 0xf9, 0x0e, 0xd6, 0x53, 0xce,
+	0xfc, 0x24, 0x0e, 0x42, 0xd4, 0xb1, 0xae, 0x98, 0xa1, 0x85, 0x19, 0x9e, 0x95, 0x0c, 0x74, 0x99,
+	0x46, 0x5e, 0xc1, 0x5a, 0xb8, 0x98, 0x8f, 0xec, 0xbc, 0xf2, 0x1d, 0x51, 0x9a, 0x1b, 0xba, 0x44,
+	0x72, 0xfe, 0xd0, 0x60, 0xad, 0xec, 0x94, 0x3c, 0x83, 0xea, 0x6d, 0x18, 0x07, 0xf9, 0xd6, 0xda,
+	0x5c, 0xfd, 0x68, 0xfb, 0x28, 0x8c, 0x03, 0xaa, 0x28, 0x1f, 0xdb, 0xb6, 0x7e, 0x18, 0x14, 0xdb,
+	0xd6, 0x0f, 0x03, 0xa7, 0x0d, 0x55, 0x7c, 0x87, 0x00, 0xd4, 0xdd, 0xf7, 0xfd, 0xe1, 0x68, 0x68,
+	0x55, 0x50, 0xee, 0x1e, 0x0c, 0xdd, 0x93, 0x91, 0xa5, 0x91, 0x0d, 0x80, 0x5e, 0xff, 0xf0, 0xd2,
+	0x3d, 0xbf, 0xe8, 0x1e, 0x0f, 0x2d, 0xdd, 0xf9, 0x05, 0xd6, 0x4a, 0xe1, 0x0a, 0xf2, 0x12, 0x5a,
+	0xa5, 0x88, 0x6d, 0xed, 0xe1, 0xac, 0xca, 0x1c, 0x5c, 0x4d, 0xbe, 0x9c, 0xd9, 0xfa, 0x62, 0x35,
+	0xf5, 0xe4, 0x8c, 0x22, 0xd6, 0xd9, 0xc7, 0xed, 0x10, 0x25, 0x92, 0xb9, 0x33, 0xe6, 0x4f, 0x25,
+	0x23, 0x5f, 0x41, 0xa3, 0x10, 0x57, 0x9d, 0x6e, 0xa9, 0xa5, 0x97, 0xdd, 0x9e, 0x95, 0xce, 0x9f,
+	0x1a, 0xc0, 0x80, 0x45, 0x63, 0xc6, 0xc5, 0x4d, 0x98, 0x92, 0x2f, 0xa0, 0xd9, 0x0d, 0x82, 0x77,
+	0x89, 0x64, 0x9c, 0x34, 0x55, 0x95, 0x18, 0xe3, 0x4b, 0x6f, 0x90, 0x5d, 0x68, 0x75, 0x83, 0xe0,
+	0x24, 0x89, 0xff, 0x8f, 0x75, 0xa8, 0x22, 0xfa, 0x4f, 0xd6, 0x97, 0x00, 0x18, 0xf7, 0x1d, 0x3b,
+	0x63, 0x1f, 0x25, 0x75, 0x0e, 0xa1, 0x8e, 0x97, 0x15, 0xe3, 0xe4, 0x35, 0x98, 0xf7, 0xd7, 0x16,
+	0x51, 0x57, 0xcf, 0xea, 0x8d, 0xb7, 0xb5, 0xb9, 0x82, 0x66, 0x77, 0x9b, 0x53, 0xe9, 0xbc, 0x01,
+	0xc8, 0xae, 0xd7, 0xb7, 0x8c, 0x05, 0xe4, 0x05, 0x98, 0xf7, 0xbf, 0x07, 0x99, 0xa7, 0xd5, 0xbf,
+	0x85, 0x3c, 0x06, 0xfc, 0x21, 0x70, 0x2a, 0x2f, 0xb4, 0x03, 0xfd, 0xe7, 0xca, 0xb8, 0xae, 0xfe,
+	0x56, 0x5e, 0xfd, 0x33, 0x00, 0xda, 0xa1, 0xb7, 0x08, 0xc6, 0x08, 0x00, 0x00,
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/raft"
 	"github.com/icetrays/icetrays/consensus/pb"
 	"github.com/icetrays/icetrays/consensus/state"
+	"github.com/prometheus/client_golang/prometheus"
 	"time"
 )
 
@@ -18,6 +19,9 @@ type Executor interface {
 type OnlyOneCanDo interface {
 	Lock() state.SnapShot
 	Execute(ins *pb.Instruction) error
+	ExecuteLimited(ins *pb.Instruction) error
+	Authorize(ins *pb.Instruction) error
+	Expand(ins *pb.Instruction) (*pb.Instruction, error)
 	UnLock() state.SnapShot
 	SnapShot() state.SnapShot
 	RollBack(shot state.SnapShot) error
@@ -29,13 +33,24 @@ type preCommitter struct {
 }
 
 func (r preCommitter) Call(instructions []*pb.Instruction) []error {
+	defer prometheus.NewTimer(preCommitDuration).ObserveDuration()
 	errs := make([]error, len(instructions))
 	copyIns := make([]*pb.Instruction, 0, len(instructions))
 	snapshot := r.preExecutor.Lock()
 	for index, ins := range instructions {
-		errs[index] = r.preExecutor.Execute(ins)
-		if errs[index] == nil {
-			copyIns = append(copyIns, ins)
+		// globs are resolved here so that followers apply the same paths,
+		// and ACLs and quotas are only enforced here so that they never
+		// diverge
+		expanded, err := r.preExecutor.Expand(ins)
+		if err == nil {
+			err = r.preExecutor.Authorize(expanded)
+		}
+		if err == nil {
+			err = r.preExecutor.ExecuteLimited(expanded)
+		}
+		errs[index] = err
+		if err == nil {
+			copyIns = append(copyIns, expanded)
 		}
 	}
 	after := r.preExecutor.UnLock()
@@ -55,7 +70,7 @@ func (r preCommitter) Call(instructions []*pb.Instruction) []error {
 		return CopyError(err, len(instructions))
 	}
 	future := r.Apply(bs, defaultTimeout)
-	if future.Error() != nil {
+	if err := future.Error(); err != nil {
 		return CopyError(err, len(instructions))
 	}
 	switch res := future.Response().(type) {
@@ -0,0 +1,76 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/consensus/state"
+)
+
+// Consistency selects how fresh a read served by a Node must be.
+type Consistency string
+
+const (
+	// Stale reads the local state, a follower may lag behind the leader.
+	Stale Consistency = "stale"
+	// Leader reads at the leader's applied index, trusting its lease
+	// instead of contacting a quorum.
+	Leader Consistency = "leader"
+	// Linearizable confirms leadership with a quorum and waits for every
+	// committed entry to be applied before reading.
+	Linearizable Consistency = "linearizable"
+)
+
+var ErrInvalidConsistency = errors.New("invalid consistency")
+
+func ParseConsistency(s string) (Consistency, error) {
+	switch c := Consistency(s); c {
+	case "":
+		return Stale, nil
+	case Stale, Leader, Linearizable:
+		return c, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidConsistency, s)
+	}
+}
+
+// ReadIndexer returns the index a read must wait for on the leader. With
+// verify it confirms leadership with a quorum first.
+type ReadIndexer interface {
+	ReadIndex(verify bool) (uint64, error)
+}
+
+type raftReader struct {
+	r     *raft.Raft
+	state *state.FileTreeState
+}
+
+func (rr raftReader) ReadIndex(verify bool) (uint64, error) {
+	if rr.r.State() != raft.Leader {
+		return 0, raft.ErrNotLeader
+	}
+	if verify {
+		if err := rr.r.VerifyLeader().Error(); err != nil {
+			return 0, err
+		}
+		if err := rr.r.Barrier(defaultTimeout).Error(); err != nil {
+			return 0, err
+		}
+	}
+	return rr.state.Index(), nil
+}
+
+type ReadIndexServer struct {
+	reader ReadIndexer
+}
+
+func (s ReadIndexServer) ReadIndex(ctx context.Context, req *pb.ReadIndexRequest) (*pb.ReadIndexResponse, error) {
+	index, err := s.reader.ReadIndex(req.GetVerify())
+	return &pb.ReadIndexResponse{Index: index}, statusError(err)
+}
+
+func (s ReadIndexServer) mustEmbedUnimplementedReaderServer() {
+
+}
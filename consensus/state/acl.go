@@ -0,0 +1,200 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidACL   = errors.New("invalid acl")
+	ErrAccessDenied = errors.New("access denied")
+)
+
+// aclOps are the instructions an ACL can allow, they are the ones that
+// change the tree at a path.
+var aclOps = map[string]pb.Instruction_Code{
+	"cp":      pb.Instruction_CP,
+	"mv":      pb.Instruction_MV,
+	"rm":      pb.Instruction_RM,
+	"mkdir":   pb.Instruction_MKDIR,
+	"restore": pb.Instruction_RESTORE,
+	"symlink": pb.Instruction_SYMLINK,
+	"setattr": pb.Instruction_SETATTR,
+	"quota":   pb.Instruction_QUOTA,
+}
+
+// ACL allows Principal the Ops on Prefix and every path below it. A
+// principal without ACLs is only limited by its role, one with ACLs may
+// only change the paths they allow.
+type ACL struct {
+	Principal string   `json:"principal"`
+	Prefix    string   `json:"prefix"`
+	Ops       []string `json:"ops"`
+}
+
+func (a ACL) allows(op, path string) bool {
+	if !(path == a.Prefix || a.Prefix == "/" || strings.HasPrefix(path, a.Prefix+"/")) {
+		return false
+	}
+	for _, o := range a.Ops {
+		if o == "*" || o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckACL validates an ACL instruction before it is submitted.
+func CheckACL(acl *pb.Acl) error {
+	if acl.GetPrincipal() == "" {
+		return fmt.Errorf("%w: principal must not be empty", ErrInvalidACL)
+	}
+	for _, op := range acl.GetOps() {
+		if _, ok := aclOps[op]; !ok && op != "*" {
+			return fmt.Errorf("%w: unknown op %q", ErrInvalidACL, op)
+		}
+	}
+	return nil
+}
+
+func (fs *FileTreeState) acl(acl *pb.Acl, params ...string) error {
+	if len(params) != 1 {
+		return ErrParamsNum
+	}
+	p, err := CheckPath(params[0])
+	if err != nil {
+		return err
+	}
+	if err := CheckACL(acl); err != nil {
+		return err
+	}
+	p = attrKey(p)
+	fs.aclMtx.Lock()
+	defer fs.aclMtx.Unlock()
+	key := aclKey(acl.GetPrincipal(), p)
+	if len(acl.GetOps()) == 0 {
+		delete(fs.acls, key)
+		return nil
+	}
+	ops := append([]string(nil), acl.GetOps()...)
+	sort.Strings(ops)
+	fs.acls[key] = ACL{Principal: acl.GetPrincipal(), Prefix: p, Ops: ops}
+	return nil
+}
+
+// ACLs returns every ACL ordered by principal and prefix.
+func (fs *FileTreeState) ACLs() []ACL {
+	fs.aclMtx.RLock()
+	defer fs.aclMtx.RUnlock()
+	acls := make([]ACL, 0, len(fs.acls))
+	for _, a := range fs.acls {
+		acls = append(acls, a)
+	}
+	sort.Slice(acls, func(i, j int) bool {
+		if acls[i].Principal != acls[j].Principal {
+			return acls[i].Principal < acls[j].Principal
+		}
+		return acls[i].Prefix < acls[j].Prefix
+	})
+	return acls
+}
+
+// Authorize fails with ErrAccessDenied when the principal of ins has ACLs
+// and none of them allows a path ins changes. The leader calls it while
+// pre-executing, after globs are expanded, so that followers never see a
+// denied instruction.
+func (fs *FileTreeState) Authorize(ins *pb.Instruction) error {
+	principal := ins.GetPrincipal()
+	if principal == "" {
+		return nil
+	}
+	fs.aclMtx.RLock()
+	var acls []ACL
+	for _, a := range fs.acls {
+		if a.Principal == principal {
+			acls = append(acls, a)
+		}
+	}
+	fs.aclMtx.RUnlock()
+	if len(acls) == 0 {
+		return nil
+	}
+	return authorize(acls, ins)
+}
+
+func authorize(acls []ACL, ins *pb.Instruction) error {
+	if ins.GetCode() == pb.Instruction_TX {
+		for _, step := range ins.GetTx().GetInstructions() {
+			if err := authorize(acls, step); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	op := strings.ToLower(ins.GetCode().String())
+	if _, ok := aclOps[op]; !ok {
+		return nil
+	}
+	for _, p := range changedPaths(ins) {
+		allowed := false
+		for _, a := range acls {
+			if a.allows(op, p) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: %s %s", ErrAccessDenied, op, p)
+		}
+	}
+	return nil
+}
+
+// changedPaths are the tree paths ins writes to, the source of cp is only
+// read and so needs no ACL.
+func changedPaths(ins *pb.Instruction) []string {
+	params := ins.GetParams()
+	if len(params) == 0 {
+		return nil
+	}
+	paths := []string{params[0]}
+	if ins.GetCode() == pb.Instruction_MV && len(params) > 1 {
+		paths = append(paths, params[1])
+	}
+	for i, p := range paths {
+		if cleaned, err := CheckPath(p); err == nil {
+			p = cleaned
+		}
+		paths[i] = attrKey(p)
+	}
+	return paths
+}
+
+func aclKey(principal, prefix string) string {
+	return principal + "\x00" + prefix
+}
+
+func (fs *FileTreeState) copyACLs() map[string]ACL {
+	fs.aclMtx.RLock()
+	defer fs.aclMtx.RUnlock()
+	if len(fs.acls) == 0 {
+		return nil
+	}
+	acls := make(map[string]ACL, len(fs.acls))
+	for k, a := range fs.acls {
+		acls[k] = a
+	}
+	return acls
+}
+
+func (fs *FileTreeState) setACLs(acls map[string]ACL) {
+	fs.aclMtx.Lock()
+	defer fs.aclMtx.Unlock()
+	fs.acls = make(map[string]ACL, len(acls))
+	for k, a := range acls {
+		fs.acls[k] = a
+	}
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"errors"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"strings"
+	"testing"
+)
+
+func TestACL(t *testing.T) {
+	fs := newTestState(t)
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_ACL, Params: []string{"/team-a/"}, Acl: &pb.Acl{Principal: "ana", Ops: []string{"rm", "mkdir"}}})
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_ACL, Params: []string{"/shared"}, Acl: &pb.Acl{Principal: "ana", Ops: []string{"*"}}})
+
+	cases := []struct {
+		ins     *pb.Instruction
+		allowed bool
+	}{
+		{&pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/team-a/x"}, Principal: "ana"}, true},
+		{&pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/team-b/x"}, Principal: "ana"}, false},
+		{&pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/team-ab"}, Principal: "ana"}, false},
+		{&pb.Instruction{Code: pb.Instruction_CP, Params: []string{"/team-a/x", "/team-b/x"}, Principal: "ana"}, false},
+		{&pb.Instruction{Code: pb.Instruction_CP, Params: []string{"/shared/x", "/team-b/x"}, Principal: "ana"}, true},
+		{&pb.Instruction{Code: pb.Instruction_MV, Params: []string{"/team-b/x", "/shared/x"}, Principal: "ana"}, false},
+		{&pb.Instruction{Code: pb.Instruction_TX, Principal: "ana", Tx: &pb.Transaction{Instructions: []*pb.Instruction{
+			{Code: pb.Instruction_MKDIR, Params: []string{"/team-a/y"}},
+			{Code: pb.Instruction_MKDIR, Params: []string{"/team-b/y"}},
+		}}}, false},
+		{&pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/team-b/x"}, Principal: "bob"}, true},
+		{&pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/team-b/x"}}, true},
+	}
+	for i, tc := range cases {
+		err := fs.Authorize(tc.ins)
+		if tc.allowed && err != nil || !tc.allowed && !errors.Is(err, ErrAccessDenied) {
+			t.Errorf("case %d: got %v, allowed %t", i, err, tc.allowed)
+		}
+	}
+
+	// ACLs are part of the snapshot and removed by an entry without ops
+	ss := fs.SnapShot()
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_ACL, Params: []string{"/shared"}, Acl: &pb.Acl{Principal: "ana"}})
+	if acls := fs.ACLs(); len(acls) != 1 || acls[0].Prefix != "/team-a" {
+		t.Fatalf("got %+v", acls)
+	}
+	if err := fs.Unmarshal(strings.NewReader(ss.String())); err != nil {
+		t.Fatal(err)
+	}
+	if acls := fs.ACLs(); len(acls) != 2 {
+		t.Fatalf("got %+v after restoring the snapshot", acls)
+	}
+}
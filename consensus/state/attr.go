@@ -0,0 +1,258 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-mfs"
+	gopath "path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidAttr = errors.New("invalid attribute")
+
+// Attrs are the attributes of a tree entry. UnixFS 1.0 nodes can not carry
+// them, so they are kept in the replicated state by path, beside the tree.
+type Attrs struct {
+	Mode     uint32            `json:"mode,omitempty"`
+	Mtime    *time.Time        `json:"mtime,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func (a Attrs) empty() bool {
+	return a.Mode == 0 && a.Mtime == nil && len(a.Metadata) == 0
+}
+
+func (a Attrs) copy() Attrs {
+	c := Attrs{Mode: a.Mode, Mtime: a.Mtime}
+	if len(a.Metadata) > 0 {
+		c.Metadata = make(map[string]string, len(a.Metadata))
+		for k, v := range a.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return c
+}
+
+// Attrs returns the attributes of path, symlinks are not followed.
+func (fs *FileTreeState) Attrs(path string) Attrs {
+	p, err := CheckPath(path)
+	if err != nil {
+		return Attrs{}
+	}
+	fs.attrMtx.RLock()
+	defer fs.attrMtx.RUnlock()
+	return fs.attrs[attrKey(p)].copy()
+}
+
+// LsAttrs returns the attributes of the entries Ls lists for path, by name.
+func (fs *FileTreeState) LsAttrs(path string) (map[string]Attrs, error) {
+	resolved, fsn, err := fs.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fsn.(*mfs.Directory); !ok {
+		_, name := gopath.Split(path)
+		return map[string]Attrs{name: fs.Attrs(resolved)}, nil
+	}
+	fs.attrMtx.RLock()
+	defer fs.attrMtx.RUnlock()
+	attrs := make(map[string]Attrs)
+	for p, a := range fs.attrs {
+		if p != "/" && gopath.Dir(p) == resolved {
+			attrs[gopath.Base(p)] = a.copy()
+		}
+	}
+	return attrs, nil
+}
+
+// CheckAttrs rejects metadata with empty keys.
+func CheckAttrs(attrs *pb.Attributes) error {
+	for k := range attrs.GetMetadata() {
+		if k == "" || strings.ContainsRune(k, 0) {
+			return fmt.Errorf("%w: metadata key %q", ErrInvalidAttr, k)
+		}
+	}
+	return nil
+}
+
+func (fs *FileTreeState) setattr(attrs *pb.Attributes, params ...string) error {
+	if len(params) != 1 {
+		return ErrParamsNum
+	}
+	p, err := CheckPath(params[0])
+	if err != nil {
+		return err
+	}
+	if err := CheckAttrs(attrs); err != nil {
+		return err
+	}
+	if _, err := mfs.Lookup(fs.root, p); err != nil {
+		return err
+	}
+	key := attrKey(p)
+	fs.attrMtx.Lock()
+	defer fs.attrMtx.Unlock()
+	a := fs.attrs[key].copy()
+	if attrs.GetMode() != 0 {
+		a.Mode = attrs.GetMode()
+	}
+	if attrs.GetMtime() != 0 {
+		mtime := time.Unix(0, attrs.GetMtime()).UTC()
+		a.Mtime = &mtime
+	}
+	for k, v := range attrs.GetMetadata() {
+		if v == "" {
+			delete(a.Metadata, k)
+			continue
+		}
+		if a.Metadata == nil {
+			a.Metadata = make(map[string]string)
+		}
+		a.Metadata[k] = v
+	}
+	if a.empty() {
+		delete(fs.attrs, key)
+	} else {
+		fs.attrs[key] = a
+	}
+	return nil
+}
+
+// moveAttrs moves the attributes of src and everything below it to dst,
+// copyAttrs copies them and dropAttrs removes them.
+func (fs *FileTreeState) moveAttrs(src, dst string) {
+	fs.relinkAttrs(src, dst, true)
+}
+
+func (fs *FileTreeState) copyAttrs(src, dst string) {
+	fs.relinkAttrs(src, dst, false)
+}
+
+func (fs *FileTreeState) relinkAttrs(src, dst string, move bool) {
+	src, dst = attrKey(src), attrKey(dst)
+	fs.attrMtx.Lock()
+	defer fs.attrMtx.Unlock()
+	// the destination is replaced as a whole, like the entry it belongs to
+	for _, p := range under(fs.attrs, dst) {
+		delete(fs.attrs, p)
+	}
+	for _, p := range under(fs.attrs, src) {
+		rel := strings.TrimPrefix(p, src)
+		if src == "/" && p != "/" {
+			rel = p
+		}
+		fs.attrs[dst+rel] = fs.attrs[p].copy()
+		if move {
+			delete(fs.attrs, p)
+		}
+	}
+}
+
+func (fs *FileTreeState) dropAttrs(path string) {
+	fs.attrMtx.Lock()
+	defer fs.attrMtx.Unlock()
+	for _, p := range under(fs.attrs, attrKey(path)) {
+		delete(fs.attrs, p)
+	}
+}
+
+// Subtree returns the attributes of path and the entries below it, by path
+// relative to path, for a RESTORE of it. Symlinks are not followed.
+func (fs *FileTreeState) Subtree(path string) (map[string]*pb.Attributes, error) {
+	p, err := CheckPath(path)
+	if err != nil {
+		return nil, err
+	}
+	p = attrKey(p)
+	fs.attrMtx.RLock()
+	defer fs.attrMtx.RUnlock()
+	attrs := make(map[string]*pb.Attributes)
+	for _, k := range under(fs.attrs, p) {
+		rel := strings.TrimPrefix(k, p)
+		if p == "/" && k != "/" {
+			rel = k
+		}
+		a := fs.attrs[k]
+		pa := &pb.Attributes{Mode: a.Mode, Metadata: a.copy().Metadata}
+		if a.Mtime != nil {
+			pa.Mtime = a.Mtime.UnixNano()
+		}
+		attrs[rel] = pa
+	}
+	return attrs, nil
+}
+
+// replaceAttrs drops the attributes of path and everything below it and puts
+// attrs, keyed like Subtree returns them, in their place.
+func (fs *FileTreeState) replaceAttrs(path string, attrs map[string]*pb.Attributes) {
+	path = attrKey(path)
+	fs.attrMtx.Lock()
+	defer fs.attrMtx.Unlock()
+	for _, p := range under(fs.attrs, path) {
+		delete(fs.attrs, p)
+	}
+	for rel, pa := range attrs {
+		p := path + rel
+		if path == "/" && rel != "" {
+			p = rel
+		}
+		a := Attrs{Mode: pa.GetMode()}
+		if pa.GetMtime() != 0 {
+			mtime := time.Unix(0, pa.GetMtime()).UTC()
+			a.Mtime = &mtime
+		}
+		for k, v := range pa.GetMetadata() {
+			if a.Metadata == nil {
+				a.Metadata = make(map[string]string)
+			}
+			a.Metadata[k] = v
+		}
+		if !a.empty() {
+			fs.attrs[p] = a
+		}
+	}
+}
+
+// under returns path and the paths below it that have attributes, sorted.
+func under(attrs map[string]Attrs, path string) []string {
+	var ps []string
+	for p := range attrs {
+		if p == path || strings.HasPrefix(p, path+"/") || path == "/" {
+			ps = append(ps, p)
+		}
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+func attrKey(p string) string {
+	if p != "/" {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+func (fs *FileTreeState) copyAttrMap() map[string]Attrs {
+	fs.attrMtx.RLock()
+	defer fs.attrMtx.RUnlock()
+	if len(fs.attrs) == 0 {
+		return nil
+	}
+	attrs := make(map[string]Attrs, len(fs.attrs))
+	for p, a := range fs.attrs {
+		attrs[p] = a.copy()
+	}
+	return attrs
+}
+
+func (fs *FileTreeState) setAttrMap(attrs map[string]Attrs) {
+	fs.attrMtx.Lock()
+	defer fs.attrMtx.Unlock()
+	fs.attrs = make(map[string]Attrs, len(attrs))
+	for p, a := range attrs {
+		fs.attrs[p] = a.copy()
+	}
+}
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"testing"
+)
+
+func TestAttrsFollowEntries(t *testing.T) {
+	fs := newTestState(t)
+	ctx := context.Background()
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/a"}})
+	addFile(t, fs, "/a/f", "f")
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_SETATTR, Params: []string{"/a/f"}, Attrs: &pb.Attributes{
+		Mode:     0644,
+		Metadata: map[string]string{"owner": "ops", "class": "hot"},
+	}})
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_SETATTR, Params: []string{"/a/f"}, Attrs: &pb.Attributes{
+		Metadata: map[string]string{"class": ""},
+	}})
+	st, err := fs.Stat(ctx, "/a/f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode != 0644 || len(st.Metadata) != 1 || st.Metadata["owner"] != "ops" {
+		t.Fatalf("got attrs %+v", st.Attrs)
+	}
+
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_CP, Params: []string{"/c", "/a"}})
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MV, Params: []string{"/a", "/b"}})
+	if a := fs.Attrs("/a/f"); a.Mode != 0 {
+		t.Fatalf("attrs left behind by mv: %+v", a)
+	}
+	for _, p := range []string{"/b/f", "/c/f"} {
+		if a := fs.Attrs(p); a.Metadata["owner"] != "ops" {
+			t.Fatalf("%s: got attrs %+v", p, a)
+		}
+	}
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/b"}})
+	if a := fs.Attrs("/b/f"); a.Mode != 0 {
+		t.Fatalf("attrs left behind by rm: %+v", a)
+	}
+
+	ss := fs.SnapShot()
+	if len(ss.Attrs) != 1 || ss.Attrs["/c/f"].Mode != 0644 {
+		t.Fatalf("snapshot attrs %+v", ss.Attrs)
+	}
+}
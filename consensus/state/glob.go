@@ -0,0 +1,157 @@
+package state
+
+import (
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-mfs"
+	"os"
+	gopath "path"
+	"sort"
+	"strings"
+)
+
+// HasMeta reports whether p contains glob patterns.
+func HasMeta(p string) bool {
+	return strings.ContainsAny(p, `*?[\`)
+}
+
+// Glob returns the paths matching pattern in lexical order, pattern uses the
+// syntax of path.Match in every element.
+func (fs *FileTreeState) Glob(pattern string) ([]string, error) {
+	p, err := CheckPath(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gopath.Match(p, ""); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPath, err)
+	}
+	matches := []string{"/"}
+	for _, elem := range strings.Split(strings.Trim(p, "/"), "/") {
+		var next []string
+		for _, m := range matches {
+			names, err := fs.match(m, elem)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				next = append(next, gopath.Join(m, name))
+			}
+		}
+		matches = next
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// match returns the names in dir matching elem, dir not being a directory
+// yields none.
+func (fs *FileTreeState) match(dir, elem string) ([]string, error) {
+	if !HasMeta(elem) {
+		if _, err := mfs.Lookup(fs.root, gopath.Join(dir, elem)); err != nil {
+			if err == os.ErrNotExist {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []string{elem}, nil
+	}
+	nd, err := mfs.Lookup(fs.root, dir)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := nd.(*mfs.Directory)
+	if !ok {
+		return nil, nil
+	}
+	names, err := d.ListNames(fs.ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matched []string
+	for _, name := range names {
+		if ok, _ := gopath.Match(elem, name); ok {
+			matched = append(matched, name)
+		}
+	}
+	return matched, nil
+}
+
+// Expand replaces the glob patterns in the rm path or cp and mv source of
+// ins by the paths they match, the leader calls it before committing so
+// that every node applies the same paths. Several matches turn ins into a
+// transaction with one step per match, sources are then copied or moved
+// into the destination directory. Transaction steps are expanded against
+// the tree as it is before the transaction runs.
+func (fs *FileTreeState) Expand(ins *pb.Instruction) (*pb.Instruction, error) {
+	if ins.GetCode() == pb.Instruction_TX {
+		tx := ins.GetTx()
+		steps := make([]*pb.Instruction, 0, len(tx.GetInstructions()))
+		for _, step := range tx.GetInstructions() {
+			expanded, err := fs.Expand(step)
+			if err != nil {
+				return nil, err
+			}
+			if expanded.GetCode() == pb.Instruction_TX {
+				steps = append(steps, expanded.GetTx().GetInstructions()...)
+			} else {
+				steps = append(steps, expanded)
+			}
+		}
+		return &pb.Instruction{
+			Code:      pb.Instruction_TX,
+			Tx:        &pb.Transaction{Preconditions: tx.GetPreconditions(), Instructions: steps},
+			Principal: ins.GetPrincipal(),
+			Origin:    ins.GetOrigin(),
+		}, nil
+	}
+	// entries without flags never expand, see pb.Flags
+	if ins.GetFlags() == nil {
+		return ins, nil
+	}
+	params := ins.GetParams()
+	var pattern string
+	switch ins.GetCode() {
+	case pb.Instruction_RM:
+		if len(params) != 1 {
+			return nil, ErrParamsNum
+		}
+		pattern = params[0]
+	case pb.Instruction_CP, pb.Instruction_MV:
+		if len(params) != 2 {
+			return nil, ErrParamsNum
+		}
+		pattern = params[1]
+		if ins.GetCode() == pb.Instruction_MV {
+			pattern = params[0]
+		}
+	default:
+		return ins, nil
+	}
+	if !strings.HasPrefix(pattern, "/") || !HasMeta(pattern) {
+		return ins, nil
+	}
+	matches, err := fs.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 && !(ins.GetCode() == pb.Instruction_RM && ins.GetFlags().GetForce()) {
+		return nil, fmt.Errorf("%s: %w", pattern, os.ErrNotExist)
+	}
+	steps := make([]*pb.Instruction, 0, len(matches))
+	for _, m := range matches {
+		step := &pb.Instruction{Code: ins.GetCode(), Flags: ins.GetFlags(), Principal: ins.GetPrincipal(), Origin: ins.GetOrigin()}
+		switch ins.GetCode() {
+		case pb.Instruction_RM:
+			step.Params = []string{m}
+		case pb.Instruction_CP:
+			step.Params = []string{gopath.Join(params[0], gopath.Base(m)), m}
+		case pb.Instruction_MV:
+			step.Params = []string{m, gopath.Join(params[1], gopath.Base(m))}
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 1 {
+		return steps[0], nil
+	}
+	return &pb.Instruction{Code: pb.Instruction_TX, Tx: &pb.Transaction{Instructions: steps}, Principal: ins.GetPrincipal(), Origin: ins.GetOrigin()}, nil
+}
@@ -0,0 +1,90 @@
+package state
+
+import (
+	"errors"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRmFlags(t *testing.T) {
+	fs := newTestState(t)
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/dir"}})
+	addFile(t, fs, "/dir/a", "a")
+	rm := func(path string, flags *pb.Flags) error {
+		return fs.Execute(&pb.Instruction{Code: pb.Instruction_RM, Params: []string{path}, Flags: flags})
+	}
+	if err := rm("/missing/a", &pb.Flags{}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want ErrNotExist", err)
+	}
+	if err := rm("/missing/a", &pb.Flags{Force: true}); err != nil {
+		t.Fatalf("forced rm of a missing path: %s", err)
+	}
+	if err := rm("/dir", &pb.Flags{}); !errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("got %v, want ErrNotEmpty", err)
+	}
+	if err := rm("/dir", &pb.Flags{Recursive: true}); err != nil {
+		t.Fatal(err)
+	}
+	// entries without flags keep ignoring missing paths
+	if err := rm("/dir", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCpParents(t *testing.T) {
+	fs := newTestState(t)
+	addFile(t, fs, "/a", "a")
+	cp := func(flags *pb.Flags) error {
+		return fs.Execute(&pb.Instruction{Code: pb.Instruction_CP, Params: []string{"/x/y/a", "/a"}, Flags: flags})
+	}
+	if err := cp(&pb.Flags{}); err == nil {
+		t.Fatal("cp into a missing directory succeeded without parents")
+	}
+	if err := cp(&pb.Flags{Parents: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat(fs.ctx, "/x/y/a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	fs := newTestState(t)
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/logs"}})
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/other"}})
+	for _, p := range []string{"/logs/2026-02", "/logs/2026-01", "/logs/2025-12", "/other/2026-03"} {
+		addFile(t, fs, p, p)
+	}
+	matches, err := fs.Glob("/*/2026-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/logs/2026-01", "/logs/2026-02", "/other/2026-03"}
+	if !reflect.DeepEqual(matches, want) {
+		t.Fatalf("got %v, want %v", matches, want)
+	}
+
+	ins, err := fs.Expand(&pb.Instruction{Code: pb.Instruction_MV, Params: []string{"/logs/2026-*", "/archive"}, Flags: &pb.Flags{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	for _, step := range ins.GetTx().GetInstructions() {
+		got = append(got, step.GetParams())
+	}
+	wantSteps := [][]string{{"/logs/2026-01", "/archive/2026-01"}, {"/logs/2026-02", "/archive/2026-02"}}
+	if !reflect.DeepEqual(got, wantSteps) {
+		t.Fatalf("got steps %v, want %v", got, wantSteps)
+	}
+
+	_, err = fs.Expand(&pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/nope-*"}, Flags: &pb.Flags{}})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want ErrNotExist", err)
+	}
+	legacy := &pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/logs/*"}}
+	if ins, err := fs.Expand(legacy); err != nil || ins != legacy {
+		t.Fatalf("instruction without flags expanded: %v, %v", ins, err)
+	}
+}
@@ -0,0 +1,300 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-mfs"
+	"github.com/ipfs/go-unixfs"
+	uio "github.com/ipfs/go-unixfs/io"
+	"os"
+	"sort"
+	"strings"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota limits a directory. Quotas are kept in the replicated state by path
+// and apply to whatever directory is at that path.
+type Quota struct {
+	MaxBytes   uint64 `json:"max_bytes,omitempty"`
+	MaxEntries uint64 `json:"max_entries,omitempty"`
+}
+
+// Usage is what a directory holds, next to its quota if it has one.
+type Usage struct {
+	Path    string `json:"path"`
+	Bytes   uint64 `json:"bytes"`
+	Entries uint64 `json:"entries"`
+	Quota   *Quota `json:"quota,omitempty"`
+}
+
+func (u *Usage) exceeds(q Quota) bool {
+	return (q.MaxBytes > 0 && u.Bytes > q.MaxBytes) || (q.MaxEntries > 0 && u.Entries > q.MaxEntries)
+}
+
+func (fs *FileTreeState) quota(quota *pb.Quota, params ...string) error {
+	if len(params) != 1 {
+		return ErrParamsNum
+	}
+	p, err := CheckPath(params[0])
+	if err != nil {
+		return err
+	}
+	fsn, err := mfs.Lookup(fs.root, p)
+	if err != nil {
+		return err
+	}
+	if _, ok := fsn.(*mfs.Directory); !ok {
+		return fmt.Errorf("%w: quotas are set on directories", ErrInvalidPath)
+	}
+	fs.quotaMtx.Lock()
+	defer fs.quotaMtx.Unlock()
+	q := Quota{MaxBytes: quota.GetMaxBytes(), MaxEntries: quota.GetMaxEntries()}
+	if q == (Quota{}) {
+		delete(fs.quotas, attrKey(p))
+	} else {
+		fs.quotas[attrKey(p)] = q
+	}
+	return nil
+}
+
+// Quotas returns the usage of every directory with a quota, by path.
+func (fs *FileTreeState) Quotas(ctx context.Context) ([]*Usage, error) {
+	var usages []*Usage
+	for _, p := range fs.quotaPaths() {
+		u, err := fs.Usage(ctx, p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	return usages, nil
+}
+
+// countsSize bounds the nodes whose entry counts are remembered.
+const countsSize = 1 << 18
+
+func newCounts() *lru.Cache {
+	c, _ := lru.New(countsSize)
+	return c
+}
+
+// Usage counts the cumulative size and the entries below path, which
+// follows symlinks. Entry counts are remembered by CID, so once a directory
+// was counted only the directories that changed since are listed again.
+func (fs *FileTreeState) Usage(ctx context.Context, path string) (*Usage, error) {
+	p, err := CheckPath(path)
+	if err != nil {
+		return nil, err
+	}
+	resolved, fsn, err := fs.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	nd, err := fsn.GetNode()
+	if err != nil {
+		return nil, err
+	}
+	size, err := nd.Size()
+	if err != nil {
+		return nil, err
+	}
+	u := &Usage{Path: p, Bytes: size}
+	if u.Entries, err = fs.entries(ctx, nd); err != nil {
+		return nil, err
+	}
+	fs.quotaMtx.RLock()
+	if q, ok := fs.quotas[attrKey(resolved)]; ok {
+		u.Quota = &q
+	}
+	fs.quotaMtx.RUnlock()
+	return u, nil
+}
+
+// entries counts the files, directories and symlinks below nd.
+func (fs *FileTreeState) entries(ctx context.Context, nd format.Node) (uint64, error) {
+	key := nd.Cid().KeyString()
+	if fs.counts != nil {
+		if n, ok := fs.counts.Get(key); ok {
+			return n.(uint64), nil
+		}
+	}
+	n, err := fs.count(ctx, nd)
+	if err != nil {
+		return 0, err
+	}
+	if fs.counts != nil {
+		fs.counts.Add(key, n)
+	}
+	return n, nil
+}
+
+func (fs *FileTreeState) count(ctx context.Context, nd format.Node) (uint64, error) {
+	pn, ok := nd.(*merkledag.ProtoNode)
+	if !ok {
+		return 0, nil
+	}
+	fsn, err := unixfs.FSNodeFromBytes(pn.Data())
+	if err != nil {
+		return 0, err
+	}
+	if t := fsn.Type(); t != unixfs.TDirectory && t != unixfs.THAMTShard {
+		return 0, nil
+	}
+	dir, err := uio.NewDirectoryFromNode(fs.dag, nd)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	err = dir.ForEachLink(ctx, func(link *format.Link) error {
+		n++
+		if link.Cid.Type() != cid.DagProtobuf {
+			return nil
+		}
+		if fs.counts != nil {
+			if sub, ok := fs.counts.Get(link.Cid.KeyString()); ok {
+				n += sub.(uint64)
+				return nil
+			}
+		}
+		child, err := fs.dag.Get(ctx, link.Cid)
+		if err != nil {
+			return err
+		}
+		sub, err := fs.entries(ctx, child)
+		if err != nil {
+			return err
+		}
+		n += sub
+		return nil
+	})
+	return n, err
+}
+
+// ExecuteLimited is Execute that fails with ErrQuotaExceeded when ins grows
+// a directory past its quota, the state is then left as it was. The leader
+// calls it while pre-executing so that such instructions never reach the
+// log, and directories already over a lowered quota can still shrink. Only
+// the quotas at, above or below a path ins changes are counted.
+func (fs *FileTreeState) ExecuteLimited(ins *pb.Instruction) error {
+	paths := fs.affectedQuotas(ins)
+	if len(paths) == 0 {
+		return fs.Execute(ins)
+	}
+	before := make(map[string]*Usage, len(paths))
+	for _, p := range paths {
+		u, err := fs.Usage(fs.ctx, p)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		before[p] = u
+	}
+	ss := fs.SnapShot()
+	if err := fs.Execute(ins); err != nil {
+		return err
+	}
+	for _, p := range paths {
+		u, err := fs.Usage(fs.ctx, p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err == nil && u.Quota != nil && u.exceeds(*u.Quota) && grew(before[p], u) {
+			err = fmt.Errorf("%w: %s holds %d bytes in %d entries, limits are %d bytes and %d entries",
+				ErrQuotaExceeded, p, u.Bytes, u.Entries, u.Quota.MaxBytes, u.Quota.MaxEntries)
+		}
+		if err != nil {
+			if rerr := fs.Unmarshal(strings.NewReader(ss.String())); rerr != nil {
+				return rerr
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func grew(before, after *Usage) bool {
+	return before == nil || after.Bytes > before.Bytes || after.Entries > before.Entries
+}
+
+// affectedQuotas returns the paths of the quotas whose usage ins can change.
+func (fs *FileTreeState) affectedQuotas(ins *pb.Instruction) []string {
+	changed := treePaths(ins)
+	if len(changed) == 0 {
+		return nil
+	}
+	var paths []string
+	for _, q := range fs.quotaPaths() {
+		for _, p := range changed {
+			if within(p, q) || within(q, p) {
+				paths = append(paths, q)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// treePaths returns the paths ins changes in the tree, the attributes,
+// quotas and ACLs kept beside it do not count.
+func treePaths(ins *pb.Instruction) []string {
+	switch ins.GetCode() {
+	case pb.Instruction_TX:
+		var paths []string
+		for _, step := range ins.GetTx().GetInstructions() {
+			paths = append(paths, treePaths(step)...)
+		}
+		return paths
+	case pb.Instruction_CP, pb.Instruction_MV, pb.Instruction_RM, pb.Instruction_MKDIR,
+		pb.Instruction_SYMLINK, pb.Instruction_RESTORE:
+		return changedPaths(ins)
+	default:
+		return nil
+	}
+}
+
+// within reports whether p is dir or below it.
+func within(p, dir string) bool {
+	return p == dir || dir == "/" || strings.HasPrefix(p, dir+"/")
+}
+
+func (fs *FileTreeState) quotaPaths() []string {
+	fs.quotaMtx.RLock()
+	defer fs.quotaMtx.RUnlock()
+	paths := make([]string, 0, len(fs.quotas))
+	for p := range fs.quotas {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (fs *FileTreeState) copyQuotas() map[string]Quota {
+	fs.quotaMtx.RLock()
+	defer fs.quotaMtx.RUnlock()
+	if len(fs.quotas) == 0 {
+		return nil
+	}
+	quotas := make(map[string]Quota, len(fs.quotas))
+	for p, q := range fs.quotas {
+		quotas[p] = q
+	}
+	return quotas
+}
+
+func (fs *FileTreeState) setQuotas(quotas map[string]Quota) {
+	fs.quotaMtx.Lock()
+	defer fs.quotaMtx.Unlock()
+	fs.quotas = make(map[string]Quota, len(quotas))
+	for p, q := range quotas {
+		fs.quotas[p] = q
+	}
+}
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-cid"
+	"testing"
+)
+
+func TestQuota(t *testing.T) {
+	fs := newTestState(t)
+	ctx := context.Background()
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/team"}})
+	addFile(t, fs, "/team/a", "a")
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_QUOTA, Params: []string{"/team"}, Quota: &pb.Quota{MaxEntries: 2}})
+	addFile(t, fs, "/team/b", "b")
+	addFile(t, fs, "/c", "c")
+
+	before := fs.MustGetRoot()
+	err := fs.ExecuteLimited(&pb.Instruction{Code: pb.Instruction_CP, Params: []string{"/team/c", "/c"}})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("got %v, want ErrQuotaExceeded", err)
+	}
+	if root := fs.MustGetRoot(); root != before {
+		t.Fatalf("over quota copy changed the root to %s", root)
+	}
+	u, err := fs.Usage(ctx, "/team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Entries != 2 || u.Quota == nil || u.Quota.MaxEntries != 2 {
+		t.Fatalf("got usage %+v", u)
+	}
+
+	// only quotas at, above or below a changed path are counted
+	if paths := fs.affectedQuotas(&pb.Instruction{Code: pb.Instruction_CP, Params: []string{"/other", "/c"}}); len(paths) != 0 {
+		t.Fatalf("unrelated copy checks quotas %v", paths)
+	}
+	tx := &pb.Transaction{Instructions: []*pb.Instruction{{Code: pb.Instruction_MV, Params: []string{"/c", "/team/c"}}}}
+	if paths := fs.affectedQuotas(&pb.Instruction{Code: pb.Instruction_TX, Tx: tx}); len(paths) != 1 || paths[0] != "/team" {
+		t.Fatalf("move into /team checks quotas %v", paths)
+	}
+
+	// lowering the quota below the usage still lets the directory shrink
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_QUOTA, Params: []string{"/team"}, Quota: &pb.Quota{MaxEntries: 1}})
+	rm := &pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/team/b"}, Flags: &pb.Flags{}}
+	if err := fs.ExecuteLimited(rm); err != nil {
+		t.Fatal(err)
+	}
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_QUOTA, Params: []string{"/team"}, Quota: &pb.Quota{}})
+	if usages, err := fs.Quotas(ctx); err != nil || len(usages) != 0 {
+		t.Fatalf("quota not removed: %v, %v", usages, err)
+	}
+}
+
+func TestUsageCounts(t *testing.T) {
+	fs := newTestState(t)
+	ctx := context.Background()
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/t/a"}})
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/t/b"}})
+	addFile(t, fs, "/t/a/f", "f")
+	count := func(want uint64) {
+		t.Helper()
+		u, err := fs.Usage(ctx, "/t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Entries != want {
+			t.Fatalf("got %d entries, want %d", u.Entries, want)
+		}
+	}
+	count(3)
+	a, err := fs.Stat(ctx, "/t/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fs.counts.Get(mustCid(t, a.Cid).KeyString()); !ok {
+		t.Fatal("count of /t/a not remembered")
+	}
+	addFile(t, fs, "/t/b/g", "g")
+	count(4)
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/t/a"}})
+	count(2)
+}
+
+func mustCid(t *testing.T, s string) cid.Cid {
+	c, err := cid.Decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
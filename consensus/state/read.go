@@ -0,0 +1,47 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-mfs"
+	uio "github.com/ipfs/go-unixfs/io"
+)
+
+var (
+	ErrNotFile    = errors.New("not a file")
+	ErrOutOfRange = errors.New("offset out of range")
+)
+
+// Open returns a seekable reader over the content of the file at path,
+// following symlinks.
+func (fs *FileTreeState) Open(ctx context.Context, path string) (uio.DagReader, error) {
+	_, fsn, err := fs.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fsn.(*mfs.File); !ok {
+		return nil, ErrNotFile
+	}
+	nd, err := fsn.GetNode()
+	if err != nil {
+		return nil, err
+	}
+	return uio.NewDagReader(ctx, nd, fs.dag)
+}
+
+// View returns a read-only state of the tree whose root is c and whose
+// attributes are attrs, for browsing earlier versions. It must not be used to
+// execute instructions.
+func (fs *FileTreeState) View(ctx context.Context, c cid.Cid, attrs map[string]Attrs) (*FileTreeState, error) {
+	nd, err := fs.dag.Get(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	view := &FileTreeState{dag: fs.dag, ctx: fs.ctx, counts: fs.counts, blockCounts: fs.blockCounts}
+	if err := view.setRoot(nd); err != nil {
+		return nil, err
+	}
+	view.setAttrMap(attrs)
+	return view, nil
+}
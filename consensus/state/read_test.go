@@ -0,0 +1,58 @@
+package state
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-cid"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	fs := newTestState(t)
+	addFile(t, fs, "/hello", "hello world")
+
+	r, err := fs.Open(context.Background(), "/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.Size() != 11 {
+		t.Fatalf("got size %d, want 11", r.Size())
+	}
+	if _, err := r.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != "world" {
+		t.Fatalf("got %q, want %q", bs, "world")
+	}
+	if _, err := fs.Open(context.Background(), "/"); err != ErrNotFile {
+		t.Fatalf("expected ErrNotFile, got %v", err)
+	}
+}
+
+func TestView(t *testing.T) {
+	fs := newTestState(t)
+	addFile(t, fs, "/a", "a")
+	root, err := cid.Decode(fs.MustGetRoot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/a"}})
+
+	view, err := fs.View(context.Background(), root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := view.Stat(context.Background(), "/a"); err != nil {
+		t.Fatalf("/a should exist in the earlier version: %s", err)
+	}
+	if _, err := fs.Stat(context.Background(), "/a"); err == nil {
+		t.Fatal("/a should be removed from the current version")
+	}
+}
@@ -0,0 +1,57 @@
+package state
+
+import (
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-mfs"
+	"os"
+	gopath "path"
+	"strings"
+)
+
+// restore replaces the node at params[0] with the node params[1], usually a
+// directory taken from an earlier version. Restoring "/" replaces the root.
+// The attributes under params[0] are replaced by attrs.
+func (fs *FileTreeState) restore(nodeData []byte, attrs map[string]*pb.Attributes, params ...string) error {
+	if len(params) != 2 {
+		return ErrParamsNum
+	}
+	p, err := CheckPath(params[0])
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(params[1], "/") {
+		return ErrInvalidPath
+	}
+	nd, err := fs.resolvePath(params[1], nodeData)
+	if err != nil {
+		return err
+	}
+	if p == "/" {
+		if err := fs.setRoot(nd); err != nil {
+			return err
+		}
+		fs.replaceAttrs(p, attrs)
+		return nil
+	}
+	p = strings.TrimSuffix(p, "/")
+	dir, name := gopath.Split(p)
+	pdir, err := getParentDir(fs.root, dir)
+	switch {
+	case err == os.ErrNotExist:
+		// the parents may have been removed along with the subtree
+		if err := fs.mkparents(dir); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		if err := pdir.Unlink(name); err != nil && err != os.ErrNotExist {
+			return err
+		}
+	}
+	if err := mfs.PutNode(fs.root, p, nd); err != nil {
+		return err
+	}
+	fs.replaceAttrs(p, attrs)
+	return nil
+}
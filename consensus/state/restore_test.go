@@ -0,0 +1,58 @@
+package state
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-cid"
+	"testing"
+)
+
+func TestRestore(t *testing.T) {
+	fs := newTestState(t)
+	ctx := context.Background()
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/a/b"}})
+	addFile(t, fs, "/a/b/c", "c")
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_SETATTR, Params: []string{"/a/b/c"}, Attrs: &pb.Attributes{Mode: 0600}})
+	before := fs.MustGetRoot()
+	dir, err := fs.Stat(ctx, "/a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := fs.Subtree("/a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_RM, Params: []string{"/a"}})
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/a/b"}})
+	addFile(t, fs, "/a/b/x", "x")
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_SETATTR, Params: []string{"/a/b/x"}, Attrs: &pb.Attributes{Mode: 0644}})
+
+	restore := func(path, id string, attrs map[string]*pb.Attributes) {
+		c, err := cid.Decode(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nd, err := fs.dag.Get(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exec(t, fs, &pb.Instruction{Code: pb.Instruction_RESTORE, Params: []string{path, id}, Node: nd.RawData(), Restored: attrs})
+	}
+	restore("/a/b", dir.Cid, attrs)
+	if _, err := fs.Stat(ctx, "/a/b/c"); err != nil {
+		t.Fatalf("subtree not restored: %s", err)
+	}
+	// the attributes under the path are those of the restored version
+	if a := fs.Attrs("/a/b/c"); a.Mode != 0600 {
+		t.Fatalf("attrs of /a/b/c not restored: %+v", a)
+	}
+	if a := fs.Attrs("/a/b/x"); a.Mode != 0 {
+		t.Fatalf("attrs of /a/b/x left behind: %+v", a)
+	}
+
+	addFile(t, fs, "/d", "d")
+	restore("/", before, nil)
+	if root := fs.MustGetRoot(); root != before {
+		t.Fatalf("root not restored: got %s, want %s", root, before)
+	}
+}
@@ -0,0 +1,240 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-mfs"
+	"github.com/ipfs/go-unixfs"
+	gopath "path"
+)
+
+const (
+	TypeFile      = "file"
+	TypeDirectory = "directory"
+	TypeSymlink   = "symlink"
+)
+
+type Stat struct {
+	Path           string `json:"path"`
+	Type           string `json:"type"`
+	Cid            string `json:"cid"`
+	Size           uint64 `json:"size"`
+	CumulativeSize uint64 `json:"cumulative_size"`
+	Children       int    `json:"children"`
+	// Blocks is the number of distinct blocks of the DAG, the node
+	// included.
+	Blocks int `json:"blocks"`
+	// Target is set for symlinks, which Stat follows but Tree reports.
+	Target string `json:"target,omitempty"`
+	Attrs
+}
+
+type Tree struct {
+	Stat
+	Name     string  `json:"name"`
+	Children []*Tree `json:"entries,omitempty"`
+}
+
+// Stat follows symlinks, the returned Path is the one asked for.
+func (fs *FileTreeState) Stat(ctx context.Context, path string) (*Stat, error) {
+	p, err := CheckPath(path)
+	if err != nil {
+		return nil, err
+	}
+	resolved, fsn, err := fs.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	st, err := fs.stat(ctx, p, fsn)
+	if err != nil {
+		return nil, err
+	}
+	st.Attrs = fs.Attrs(resolved)
+	return st, nil
+}
+
+// Tree walks path recursively. Directories deeper than depth are reported
+// without their entries, a negative depth walks the whole subtree. Symlinks
+// in path are followed, the result has the path they lead to, and those
+// below it are reported and not followed.
+func (fs *FileTreeState) Tree(ctx context.Context, path string, depth int) (*Tree, error) {
+	p, err := CheckPath(path)
+	if err != nil {
+		return nil, err
+	}
+	resolved, fsn, err := fs.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	return fs.tree(ctx, resolved, fsn, depth)
+}
+
+// LsTypes returns the type of the entries Ls lists for path, symlinks
+// included, by name.
+func (fs *FileTreeState) LsTypes(ctx context.Context, path string) (map[string]string, error) {
+	_, fsn, err := fs.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := fsn.(*mfs.Directory)
+	if !ok {
+		_, name := gopath.Split(path)
+		return map[string]string{name: entryType(fsn)}, nil
+	}
+	names, err := dir.ListNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]string, len(names))
+	for _, n := range names {
+		child, err := dir.Child(n)
+		if err != nil {
+			return nil, err
+		}
+		types[n] = entryType(child)
+	}
+	return types, nil
+}
+
+func entryType(fsn mfs.FSNode) string {
+	if _, ok := fsn.(*mfs.Directory); ok {
+		return TypeDirectory
+	}
+	if _, ok := symlinkTarget(fsn); ok {
+		return TypeSymlink
+	}
+	return TypeFile
+}
+
+func (fs *FileTreeState) tree(ctx context.Context, path string, fsn mfs.FSNode, depth int) (*Tree, error) {
+	st, err := fs.stat(ctx, path, fsn)
+	if err != nil {
+		return nil, err
+	}
+	st.Attrs = fs.Attrs(path)
+	_, name := gopath.Split(path)
+	t := &Tree{Stat: *st, Name: name}
+	dir, ok := fsn.(*mfs.Directory)
+	if !ok || depth == 0 {
+		return t, nil
+	}
+	names, err := dir.ListNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.Children = make([]*Tree, 0, len(names))
+	for _, n := range names {
+		child, err := dir.Child(n)
+		if err != nil {
+			return nil, err
+		}
+		sub, err := fs.tree(ctx, gopath.Join(path, n), child, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		t.Children = append(t.Children, sub)
+	}
+	return t, nil
+}
+
+func (fs *FileTreeState) stat(ctx context.Context, path string, fsn mfs.FSNode) (*Stat, error) {
+	nd, err := fsn.GetNode()
+	if err != nil {
+		return nil, err
+	}
+	cumulative, err := nd.Size()
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := fs.blocks(ctx, nd)
+	if err != nil {
+		return nil, err
+	}
+	st := &Stat{
+		Path:           path,
+		Cid:            nd.Cid().String(),
+		CumulativeSize: cumulative,
+		Blocks:         blocks,
+	}
+	switch fsn := fsn.(type) {
+	case *mfs.Directory:
+		st.Type = TypeDirectory
+		names, err := fsn.ListNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		st.Children = len(names)
+	case *mfs.File:
+		if target, ok := symlinkTarget(fsn); ok {
+			st.Type = TypeSymlink
+			st.Target = target
+			st.Size = uint64(len(target))
+			break
+		}
+		st.Type = TypeFile
+		st.Size, err = fileSize(nd)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unrecognized type")
+	}
+	return st, nil
+}
+
+func fileSize(nd format.Node) (uint64, error) {
+	switch nd := nd.(type) {
+	case *merkledag.ProtoNode:
+		fsn, err := unixfs.FSNodeFromBytes(nd.Data())
+		if err != nil {
+			return 0, err
+		}
+		return fsn.FileSize(), nil
+	case *merkledag.RawNode:
+		return uint64(len(nd.RawData())), nil
+	default:
+		return 0, errors.New("unrecognized node type")
+	}
+}
+
+// blocks counts the distinct blocks of the DAG under nd, fetching it a level
+// at a time. Raw leaves are counted without being fetched, and the counts are
+// remembered by CID.
+func (fs *FileTreeState) blocks(ctx context.Context, nd format.Node) (int, error) {
+	key := nd.Cid().KeyString()
+	if fs.blockCounts != nil {
+		if n, ok := fs.blockCounts.Get(key); ok {
+			return n.(int), nil
+		}
+	}
+	seen := map[cid.Cid]bool{nd.Cid(): true}
+	level := []format.Node{nd}
+	for len(level) > 0 {
+		var next []cid.Cid
+		for _, n := range level {
+			for _, link := range n.Links() {
+				if seen[link.Cid] {
+					continue
+				}
+				seen[link.Cid] = true
+				if link.Cid.Type() != cid.Raw {
+					next = append(next, link.Cid)
+				}
+			}
+		}
+		level = make([]format.Node, 0, len(next))
+		for opt := range fs.dag.GetMany(ctx, next) {
+			if opt.Err != nil {
+				return 0, opt.Err
+			}
+			level = append(level, opt.Node)
+		}
+	}
+	if fs.blockCounts != nil {
+		fs.blockCounts.Add(key, len(seen))
+	}
+	return len(seen), nil
+}
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"os"
+	"testing"
+)
+
+func TestStatAndTree(t *testing.T) {
+	fs := newTestState(t)
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/a/b"}})
+	addFile(t, fs, "/a/hello", "hello")
+	addFile(t, fs, "/a/b/world", "world!")
+
+	st, err := fs.Stat(context.Background(), "/a/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Type != TypeFile || st.Size != 5 || st.Children != 0 || st.Blocks != 1 {
+		t.Fatalf("unexpected file stat: %+v", st)
+	}
+	st, err = fs.Stat(context.Background(), "/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Type != TypeDirectory || st.Children != 2 || st.Blocks != 4 {
+		t.Fatalf("unexpected dir stat: %+v", st)
+	}
+	if _, err := fs.Stat(context.Background(), "/missing"); err != os.ErrNotExist {
+		t.Fatalf("expected not exist, got %v", err)
+	}
+
+	tree, err := fs.Tree(context.Background(), "/", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 2 {
+		t.Fatalf("unexpected tree: %+v", tree)
+	}
+	shallow, err := fs.Tree(context.Background(), "/", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shallow.Children) != 1 || shallow.Children[0].Children != nil {
+		t.Fatalf("depth limit not applied: %+v", shallow.Children[0])
+	}
+}
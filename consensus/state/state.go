@@ -5,12 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/icetrays/icetrays/consensus/pb"
 	"github.com/icetrays/icetrays/datastore"
 	blocks "github.com/ipfs/go-block-format"
 	"github.com/ipfs/go-cid"
 	format "github.com/ipfs/go-ipld-format"
-	"github.com/ipfs/go-merkledag"
 	"github.com/ipfs/go-mfs"
 	"github.com/ipfs/go-unixfs"
 	"io"
@@ -23,7 +23,11 @@ import (
 	"time"
 )
 
-var ErrParamsNum = errors.New("params num error")
+var (
+	ErrParamsNum   = errors.New("params num error")
+	ErrInvalidPath = errors.New("invalid path")
+	ErrNotEmpty    = errors.New("directory not empty")
+)
 
 type FileTreeState struct {
 	dag         format.DAGService
@@ -32,27 +36,56 @@ type FileTreeState struct {
 	ctx         context.Context
 	once        sync.Once
 	index       uint64
+	applying    uint64
 	mtx         sync.Mutex
 	PreExecuted bool
+	tagMtx      sync.RWMutex
+	tags        map[string]Tag
+	attrMtx     sync.RWMutex
+	attrs       map[string]Attrs
+	quotaMtx    sync.RWMutex
+	quotas      map[string]Quota
+	aclMtx      sync.RWMutex
+	acls        map[string]ACL
+	// counts remembers the entries below the nodes Usage counted and
+	// blockCounts the blocks of the nodes Stat counted, by CID.
+	counts      *lru.Cache
+	blockCounts *lru.Cache
 }
 
 func (fs *FileTreeState) Execute(ins *pb.Instruction) error {
 	switch ins.GetCode() {
 	case pb.Instruction_CP:
-		return fs.cp(ins.GetNode(), ins.GetParams()...)
+		return fs.cp(ins.GetNode(), ins.GetFlags(), ins.GetParams()...)
 	case pb.Instruction_MV:
 		return fs.Mv(ins.GetParams()...)
 	case pb.Instruction_RM:
-		return fs.Rm(ins.GetParams()...)
+		return fs.rm(ins.GetFlags(), ins.GetParams()...)
 	case pb.Instruction_MKDIR:
 		return fs.Mkdir(ins.GetParams()...)
+	case pb.Instruction_TX:
+		return fs.transact(ins.GetTx())
+	case pb.Instruction_RESTORE:
+		return fs.restore(ins.GetNode(), ins.GetRestored(), ins.GetParams()...)
+	case pb.Instruction_TAG:
+		return fs.tag(ins.GetParams()...)
+	case pb.Instruction_UNTAG:
+		return fs.untag(ins.GetParams()...)
+	case pb.Instruction_SYMLINK:
+		return fs.symlink(ins.GetParams()...)
+	case pb.Instruction_SETATTR:
+		return fs.setattr(ins.GetAttrs(), ins.GetParams()...)
+	case pb.Instruction_QUOTA:
+		return fs.quota(ins.GetQuota(), ins.GetParams()...)
+	case pb.Instruction_ACL:
+		return fs.acl(ins.GetAcl(), ins.GetParams()...)
 	default:
 		return errors.New("unrecognized operation")
 	}
 }
 
 func (fs *FileTreeState) Ls(ctx context.Context, path string) ([]mfs.NodeListing, error) {
-	fsn, err := mfs.Lookup(fs.root, path)
+	_, fsn, err := fs.lookup(path)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +130,7 @@ func (fs *FileTreeState) resolvePath(path string, nodeData []byte) (format.Node,
 	return format.DefaultBlockDecoder.Decode(blk)
 }
 
-func (fs *FileTreeState) cp(nodeData []byte, params ...string) error {
+func (fs *FileTreeState) cp(nodeData []byte, flags *pb.Flags, params ...string) error {
 	if len(params) != 2 {
 		return ErrParamsNum
 	}
@@ -105,29 +138,56 @@ func (fs *FileTreeState) cp(nodeData []byte, params ...string) error {
 	if err != nil {
 		return err
 	}
-	return mfs.PutNode(fs.root, params[0], node)
+	if flags.GetParents() {
+		dst, err := CheckPath(params[0])
+		if err != nil {
+			return err
+		}
+		dir, _ := gopath.Split(strings.TrimSuffix(dst, "/"))
+		if err := fs.mkparents(dir); err != nil {
+			return err
+		}
+	}
+	if err := mfs.PutNode(fs.root, params[0], node); err != nil {
+		return err
+	}
+	if strings.HasPrefix(params[1], "/") {
+		fs.copyAttrs(params[1], params[0])
+	}
+	return nil
 }
 
 func (fs *FileTreeState) Mv(params ...string) error {
 	if len(params) != 2 {
 		return ErrParamsNum
 	}
-	src, err := checkPath(params[0])
+	src, err := CheckPath(params[0])
 	if err != nil {
 		return err
 	}
-	dst, err := checkPath(params[1])
+	dst, err := CheckPath(params[1])
 	if err != nil {
 		return err
 	}
-	return mfs.Mv(fs.root, src, dst)
+	// like mfs.Mv, moving onto a directory moves src into it
+	target := dst
+	if nd, err := mfs.Lookup(fs.root, dst); err == nil {
+		if _, ok := nd.(*mfs.Directory); ok {
+			target = gopath.Join(dst, gopath.Base(src))
+		}
+	}
+	if err := mfs.Mv(fs.root, src, dst); err != nil {
+		return err
+	}
+	fs.moveAttrs(src, target)
+	return nil
 }
 
 func (fs *FileTreeState) Mkdir(params ...string) error {
 	if len(params) != 1 {
 		return ErrParamsNum
 	}
-	src, err := checkPath(params[0])
+	src, err := CheckPath(params[0])
 	if err != nil {
 		return err
 	}
@@ -138,29 +198,76 @@ func (fs *FileTreeState) Mkdir(params ...string) error {
 	})
 }
 
+// Rm removes params[0] the way entries without flags do: a missing path is
+// not an error and directories are removed with their content.
 func (fs *FileTreeState) Rm(params ...string) error {
+	return fs.rm(nil, params...)
+}
+
+func (fs *FileTreeState) rm(flags *pb.Flags, params ...string) error {
 	if len(params) != 1 {
 		return ErrParamsNum
 	}
-	dir, name := gopath.Split(params[0])
+	legacy := flags == nil
+	p, err := CheckPath(params[0])
+	if err != nil {
+		return err
+	}
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return fmt.Errorf("%w: can not remove the root", ErrInvalidPath)
+	}
+	dir, name := gopath.Split(p)
 
 	pdir, err := getParentDir(fs.root, dir)
 	if err != nil {
 		if err == os.ErrNotExist {
-			return nil
+			if legacy || flags.GetForce() {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", p, os.ErrNotExist)
 		}
 		return fmt.Errorf("parent lookup: %s", err)
 	}
+	if !legacy && !flags.GetRecursive() {
+		child, err := pdir.Child(name)
+		if err == nil {
+			if d, ok := child.(*mfs.Directory); ok {
+				names, err := d.ListNames(fs.ctx)
+				if err != nil {
+					return err
+				}
+				if len(names) > 0 {
+					return fmt.Errorf("%s: %w", p, ErrNotEmpty)
+				}
+			}
+		}
+	}
 	err = pdir.Unlink(name)
 	if err != nil {
 		if err == os.ErrNotExist {
-			return nil
+			if legacy || flags.GetForce() {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", p, os.ErrNotExist)
 		}
 		return err
 	}
+	fs.dropAttrs(p)
 	return pdir.Flush()
 }
 
+// mkparents creates dir and its missing parents.
+func (fs *FileTreeState) mkparents(dir string) error {
+	if dir == "/" || dir == "" {
+		return nil
+	}
+	return mfs.Mkdir(fs.root, dir, mfs.MkdirOpts{
+		Mkparents:  true,
+		CidBuilder: fs.root.GetDirectory().GetCidBuilder(),
+	})
+}
+
 func (fs *FileTreeState) Flush() error {
 	_, err := mfs.FlushPath(context.Background(), fs.root, "/")
 	if err != nil {
@@ -194,36 +301,28 @@ func (fts *FileTreeState) Marshal(writer io.Writer) error {
 }
 
 func (fts *FileTreeState) String() string {
-	d := SnapShot{
-		Index: fts.Index(),
-		Root:  fts.MustGetRoot(),
-	}
-	data, _ := json.Marshal(d)
-	return string(data)
+	return fts.SnapShot().String()
 }
 
 func (fts *FileTreeState) Lock() SnapShot {
 	fts.mtx.Lock()
-	ss := SnapShot{
-		Index: fts.Index(),
-		Root:  fts.MustGetRoot(),
-	}
-	return ss
+	return fts.SnapShot()
 }
 
 func (fts *FileTreeState) UnLock() SnapShot {
-	ss := SnapShot{
-		Index: fts.Index(),
-		Root:  fts.MustGetRoot(),
-	}
+	ss := fts.SnapShot()
 	fts.mtx.Unlock()
 	return ss
 }
 
 func (fts *FileTreeState) SnapShot() SnapShot {
 	ss := SnapShot{
-		Index: fts.Index(),
-		Root:  fts.MustGetRoot(),
+		Index:  fts.Index(),
+		Root:   fts.MustGetRoot(),
+		Tags:   fts.copyTags(),
+		Attrs:  fts.copyAttrMap(),
+		Quotas: fts.copyQuotas(),
+		ACLs:   fts.copyACLs(),
 	}
 	return ss
 }
@@ -259,6 +358,21 @@ func (fts *FileTreeState) SetIndex(idx uint64) {
 	atomic.StoreUint64(&fts.index, idx)
 }
 
+// SetApplying tells the state the index of the entry it executes next, Index
+// only moves to it once the entry was applied.
+func (fts *FileTreeState) SetApplying(idx uint64) {
+	atomic.StoreUint64(&fts.applying, idx)
+}
+
+// applyingIndex is the index of the entry being executed, or the last applied
+// one while pre-executing.
+func (fts *FileTreeState) applyingIndex() uint64 {
+	if idx := atomic.LoadUint64(&fts.applying); idx > fts.Index() {
+		return idx
+	}
+	return fts.Index()
+}
+
 func walkDirectory(ctx context.Context, dir *mfs.Directory, visited map[string]bool) error {
 	ls, err := dir.List(ctx)
 	if err != nil {
@@ -299,10 +413,7 @@ func (fts *FileTreeState) Unmarshal(reader io.Reader) error {
 	if err != nil {
 		return err
 	}
-	state := struct {
-		Index uint64 `json:"index"`
-		Root  string `json:"root"`
-	}{}
+	state := SnapShot{}
 	if err = json.Unmarshal(bs, &state); err != nil {
 		return err
 	}
@@ -314,18 +425,13 @@ func (fts *FileTreeState) Unmarshal(reader io.Reader) error {
 	if err != nil {
 		return err
 	}
-
-	rootNode, ok := raw.(*merkledag.ProtoNode)
-	if !ok {
-		return errors.New("invalid root node")
-	}
-	r, err := mfs.NewRoot(fts.ctx, fts.dag, rootNode, func(ctx context.Context, cid cid.Cid) error {
-		return nil
-	})
-	if err != nil {
+	if err := fts.setRoot(raw); err != nil {
 		return err
 	}
-	fts.root = r
+	fts.setTags(state.Tags)
+	fts.setAttrMap(state.Attrs)
+	fts.setQuotas(state.Quotas)
+	fts.setACLs(state.ACLs)
 	fts.SetIndex(state.Index)
 	return nil
 }
@@ -333,9 +439,15 @@ func (fts *FileTreeState) Unmarshal(reader io.Reader) error {
 func NewFileTreeState(store datastore.StateDB, dag format.DAGService) (*FileTreeState, error) {
 	s, err := store.LoadState()
 	state := &FileTreeState{
-		dag:   dag,
-		store: store,
-		ctx:   context.Background(),
+		dag:         dag,
+		store:       store,
+		ctx:         context.Background(),
+		tags:        make(map[string]Tag),
+		attrs:       make(map[string]Attrs),
+		quotas:      make(map[string]Quota),
+		acls:        make(map[string]ACL),
+		counts:      newCounts(),
+		blockCounts: newCounts(),
 	}
 	if err != nil {
 		if err != datastore.ErrKeyNotFound {
@@ -356,13 +468,14 @@ func NewFileTreeState(store datastore.StateDB, dag format.DAGService) (*FileTree
 	return state, nil
 }
 
-func checkPath(p string) (string, error) {
+// CheckPath validates p as an absolute tree path and returns it cleaned.
+func CheckPath(p string) (string, error) {
 	if len(p) == 0 {
-		return "", fmt.Errorf("paths must not be empty")
+		return "", fmt.Errorf("%w: paths must not be empty", ErrInvalidPath)
 	}
 
 	if p[0] != '/' {
-		return "", fmt.Errorf("paths must start with a leading slash")
+		return "", fmt.Errorf("%w: paths must start with a leading slash", ErrInvalidPath)
 	}
 
 	cleaned := gopath.Clean(p)
@@ -386,8 +499,12 @@ func getParentDir(root *mfs.Root, dir string) (*mfs.Directory, error) {
 }
 
 type SnapShot struct {
-	Index uint64 `json:"index"`
-	Root  string `json:"root"`
+	Index  uint64           `json:"index"`
+	Root   string           `json:"root"`
+	Tags   map[string]Tag   `json:"tags,omitempty"`
+	Attrs  map[string]Attrs `json:"attrs,omitempty"`
+	Quotas map[string]Quota `json:"quotas,omitempty"`
+	ACLs   map[string]ACL   `json:"acls,omitempty"`
 }
 
 func (ss SnapShot) String() string {
@@ -0,0 +1,37 @@
+package state
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/internal/testutil"
+	"github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+	"github.com/ipfs/go-unixfs"
+	"testing"
+)
+
+func newTestState(t *testing.T) *FileTreeState {
+	fs, err := NewFileTreeState(&testutil.MemStateDB{}, mdtest.Mock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func addFile(t *testing.T, fs *FileTreeState, path string, content string) {
+	nd := merkledag.NodeWithData(unixfs.FilePBData([]byte(content), uint64(len(content))))
+	if err := fs.dag.Add(context.Background(), nd); err != nil {
+		t.Fatal(err)
+	}
+	exec(t, fs, &pb.Instruction{
+		Code:   pb.Instruction_CP,
+		Params: []string{path, nd.Cid().String()},
+		Node:   nd.RawData(),
+	})
+}
+
+func exec(t *testing.T, fs *FileTreeState, ins *pb.Instruction) {
+	if err := fs.Execute(ins); err != nil {
+		t.Fatalf("%s %v: %s", ins.Code, ins.Params, err)
+	}
+}
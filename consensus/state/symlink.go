@@ -0,0 +1,134 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-mfs"
+	"github.com/ipfs/go-unixfs"
+	gopath "path"
+	"strings"
+)
+
+// maxSymlinks is how many symlinks a lookup follows before it gives up, as
+// on Linux.
+const maxSymlinks = 40
+
+var (
+	ErrNotSymlink  = errors.New("not a symlink")
+	ErrSymlinkLoop = errors.New("too many levels of symbolic links")
+)
+
+// symlink creates a UnixFS symlink at params[0] pointing to params[1]. The
+// target is stored as given and resolved when the link is read, a relative
+// target is relative to the directory holding the link.
+func (fs *FileTreeState) symlink(params ...string) error {
+	if len(params) != 2 {
+		return ErrParamsNum
+	}
+	p, err := CheckPath(params[0])
+	if err != nil {
+		return err
+	}
+	target := params[1]
+	if target == "" || strings.ContainsRune(target, 0) {
+		return fmt.Errorf("%w: bad symlink target %q", ErrInvalidPath, target)
+	}
+	data, err := unixfs.SymlinkData(target)
+	if err != nil {
+		return err
+	}
+	nd := merkledag.NodeWithData(data)
+	nd.SetCidBuilder(fs.root.GetDirectory().GetCidBuilder())
+	return mfs.PutNode(fs.root, strings.TrimSuffix(p, "/"), nd)
+}
+
+// Readlink returns the target of the symlink at path, symlinks in the
+// parents of path are followed.
+func (fs *FileTreeState) Readlink(path string) (string, error) {
+	p, err := CheckPath(path)
+	if err != nil {
+		return "", err
+	}
+	dir, name := gopath.Split(strings.TrimSuffix(p, "/"))
+	if name == "" {
+		return "", fmt.Errorf("%s: %w", p, ErrNotSymlink)
+	}
+	parent, _, err := fs.lookup(dir)
+	if err != nil {
+		return "", err
+	}
+	fsn, err := mfs.Lookup(fs.root, gopath.Join(parent, name))
+	if err != nil {
+		return "", err
+	}
+	target, ok := symlinkTarget(fsn)
+	if !ok {
+		return "", fmt.Errorf("%s: %w", p, ErrNotSymlink)
+	}
+	return target, nil
+}
+
+// lookup is mfs.Lookup that follows symlinks anywhere in path, it returns
+// the path the node was found at.
+func (fs *FileTreeState) lookup(path string) (string, mfs.FSNode, error) {
+	p, err := CheckPath(path)
+	if err != nil {
+		return "", nil, err
+	}
+	resolved := "/"
+	rest := strings.Split(p, "/")
+	for hops := 0; len(rest) > 0; {
+		elem := rest[0]
+		rest = rest[1:]
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			resolved = gopath.Dir(resolved)
+			continue
+		}
+		next := gopath.Join(resolved, elem)
+		fsn, err := mfs.Lookup(fs.root, next)
+		if err != nil {
+			return "", nil, err
+		}
+		target, ok := symlinkTarget(fsn)
+		if !ok {
+			resolved = next
+			continue
+		}
+		if hops++; hops > maxSymlinks {
+			return "", nil, fmt.Errorf("%s: %w", p, ErrSymlinkLoop)
+		}
+		if !strings.HasPrefix(target, "/") {
+			target = gopath.Join(resolved, target)
+		}
+		rest = append(strings.Split(target, "/"), rest...)
+		resolved = "/"
+	}
+	fsn, err := mfs.Lookup(fs.root, resolved)
+	if err != nil {
+		return "", nil, err
+	}
+	return resolved, fsn, nil
+}
+
+func symlinkTarget(fsn mfs.FSNode) (string, bool) {
+	if _, ok := fsn.(*mfs.File); !ok {
+		return "", false
+	}
+	nd, err := fsn.GetNode()
+	if err != nil {
+		return "", false
+	}
+	pn, ok := nd.(*merkledag.ProtoNode)
+	if !ok {
+		return "", false
+	}
+	f, err := unixfs.FSNodeFromBytes(pn.Data())
+	if err != nil || f.Type() != unixfs.TSymlink {
+		return "", false
+	}
+	return string(f.Data()), true
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSymlink(t *testing.T) {
+	fs := newTestState(t)
+	ctx := context.Background()
+	symlink := func(path, target string) {
+		exec(t, fs, &pb.Instruction{Code: pb.Instruction_SYMLINK, Params: []string{path, target}})
+	}
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_MKDIR, Params: []string{"/data/v1"}})
+	addFile(t, fs, "/data/v1/a", "content")
+	symlink("/data/latest", "v1")
+	symlink("/alias", "/data/latest")
+
+	r, err := fs.Open(ctx, "/alias/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "content" {
+		t.Fatalf("read %q through symlinks", data)
+	}
+	st, err := fs.Stat(ctx, "/alias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Type != TypeDirectory {
+		t.Fatalf("stat followed to a %s, want a directory", st.Type)
+	}
+	tree, err := fs.Tree(ctx, "/data", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range tree.Children {
+		if e.Name == "latest" && (e.Type != TypeSymlink || e.Target != "v1") {
+			t.Fatalf("tree entry %+v, want a symlink to v1", e.Stat)
+		}
+	}
+	types, err := fs.LsTypes(ctx, "/data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types["latest"] != TypeSymlink || types["v1"] != TypeDirectory {
+		t.Fatalf("ls types %v", types)
+	}
+	if target, err := fs.Readlink("/alias"); err != nil || target != "/data/latest" {
+		t.Fatalf("readlink: %q, %v", target, err)
+	}
+	if _, err := fs.Readlink("/data/v1/a"); !errors.Is(err, ErrNotSymlink) {
+		t.Fatalf("got %v, want ErrNotSymlink", err)
+	}
+
+	symlink("/loop1", "/loop2")
+	symlink("/loop2", "loop1")
+	if _, err := fs.Stat(ctx, "/loop1"); !errors.Is(err, ErrSymlinkLoop) {
+		t.Fatalf("got %v, want ErrSymlinkLoop", err)
+	}
+}
@@ -0,0 +1,137 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTag  = errors.New("invalid tag name")
+	ErrTagNotFound = fmt.Errorf("tag %w", os.ErrNotExist)
+	ErrTagExists   = fmt.Errorf("tag %w", os.ErrExist)
+)
+
+// Tag names the root the tree had when it was created. Tags are part of the
+// replicated state, so every node agrees on them.
+type Tag struct {
+	Name    string    `json:"name"`
+	Root    string    `json:"root"`
+	Index   uint64    `json:"index"`
+	Created time.Time `json:"created"`
+}
+
+func (fs *FileTreeState) Tags() []Tag {
+	fs.tagMtx.RLock()
+	defer fs.tagMtx.RUnlock()
+	tags := make([]Tag, 0, len(fs.tags))
+	for _, t := range fs.tags {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+func (fs *FileTreeState) Tag(name string) (Tag, error) {
+	fs.tagMtx.RLock()
+	defer fs.tagMtx.RUnlock()
+	t, ok := fs.tags[name]
+	if !ok {
+		return Tag{}, ErrTagNotFound
+	}
+	return t, nil
+}
+
+// TaggedRoots returns the distinct roots that are tagged.
+func (fs *FileTreeState) TaggedRoots() []string {
+	fs.tagMtx.RLock()
+	defer fs.tagMtx.RUnlock()
+	seen := make(map[string]bool)
+	roots := make([]string, 0, len(fs.tags))
+	for _, t := range fs.tags {
+		if !seen[t.Root] {
+			seen[t.Root] = true
+			roots = append(roots, t.Root)
+		}
+	}
+	return roots
+}
+
+// CheckTagName rejects names that would be ambiguous in URLs or empty, and
+// all-digit names, which would shadow the raft index of the same number.
+func CheckTagName(name string) error {
+	if name == "" || len(name) > 128 || strings.ContainsAny(name, "/ \t\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidTag, name)
+	}
+	if strings.Trim(name, "0123456789") == "" {
+		return fmt.Errorf("%w: %q is a raft index", ErrInvalidTag, name)
+	}
+	return nil
+}
+
+// tag names the current root, params[1] is the creation time chosen by the
+// node that submitted it so that it is the same everywhere.
+func (fs *FileTreeState) tag(params ...string) error {
+	if len(params) != 2 {
+		return ErrParamsNum
+	}
+	name := params[0]
+	if err := CheckTagName(name); err != nil {
+		return err
+	}
+	created, err := time.Parse(time.RFC3339Nano, params[1])
+	if err != nil {
+		return err
+	}
+	root, err := fs.Root()
+	if err != nil {
+		return err
+	}
+	fs.tagMtx.Lock()
+	defer fs.tagMtx.Unlock()
+	if _, ok := fs.tags[name]; ok {
+		return fmt.Errorf("%w: %s", ErrTagExists, name)
+	}
+	fs.tags[name] = Tag{Name: name, Root: root, Index: fs.applyingIndex(), Created: created}
+	return nil
+}
+
+func (fs *FileTreeState) untag(params ...string) error {
+	if len(params) != 1 {
+		return ErrParamsNum
+	}
+	fs.tagMtx.Lock()
+	defer fs.tagMtx.Unlock()
+	if _, ok := fs.tags[params[0]]; !ok {
+		return fmt.Errorf("%w: %s", ErrTagNotFound, params[0])
+	}
+	delete(fs.tags, params[0])
+	return nil
+}
+
+func (fs *FileTreeState) copyTags() map[string]Tag {
+	fs.tagMtx.RLock()
+	defer fs.tagMtx.RUnlock()
+	if len(fs.tags) == 0 {
+		return nil
+	}
+	tags := make(map[string]Tag, len(fs.tags))
+	for name, t := range fs.tags {
+		tags[name] = t
+	}
+	return tags
+}
+
+func (fs *FileTreeState) setTags(tags map[string]Tag) {
+	fs.tagMtx.Lock()
+	defer fs.tagMtx.Unlock()
+	fs.tags = make(map[string]Tag, len(tags))
+	for name, t := range tags {
+		fs.tags[name] = t
+	}
+}
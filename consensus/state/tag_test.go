@@ -0,0 +1,73 @@
+package state
+
+import (
+	"errors"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTags(t *testing.T) {
+	fs := newTestState(t)
+	created := time.Now().UTC().Format(time.RFC3339Nano)
+	tag := func(name string) error {
+		return fs.Execute(&pb.Instruction{Code: pb.Instruction_TAG, Params: []string{name, created}})
+	}
+	addFile(t, fs, "/a", "a")
+	tagged := fs.MustGetRoot()
+	if err := tag("v1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tag("v1"); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("got %v, want ErrExist", err)
+	}
+	for _, name := range []string{"a/b", "42"} {
+		if err := tag(name); !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("%s: got %v, want ErrInvalidTag", name, err)
+		}
+	}
+	// a tag records the index of the entry it is applied with
+	fs.SetApplying(7)
+	if err := tag("at7"); err != nil {
+		t.Fatal(err)
+	}
+	if at7, _ := fs.Tag("at7"); at7.Index != 7 {
+		t.Fatalf("tag index: got %d, want 7", at7.Index)
+	}
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_UNTAG, Params: []string{"at7"}})
+	addFile(t, fs, "/b", "b")
+
+	// tags survive a snapshot and are rolled back with the root
+	ss := fs.SnapShot()
+	if err := tag("v2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.RollBack(ss); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Tag("v2"); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("got %v, want ErrTagNotFound after rollback", err)
+	}
+	saved := fs.String()
+
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_UNTAG, Params: []string{"v1"}})
+	if tags := fs.Tags(); len(tags) != 0 {
+		t.Fatalf("got tags %v after untag", tags)
+	}
+	if err := fs.Execute(&pb.Instruction{Code: pb.Instruction_UNTAG, Params: []string{"v1"}}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want ErrNotExist", err)
+	}
+
+	if err := fs.Unmarshal(strings.NewReader(saved)); err != nil {
+		t.Fatal(err)
+	}
+	v1, err := fs.Tag("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v1.Root != tagged {
+		t.Fatalf("tag root: got %s, want %s", v1.Root, tagged)
+	}
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-mfs"
+	"os"
+	"strings"
+)
+
+var (
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrNestedTransaction  = errors.New("transactions cannot be nested")
+	// ErrConflict is returned when a CID a client expected has changed since
+	// it was read.
+	ErrConflict = errors.New("conflict")
+)
+
+// transact applies the instructions of tx in order. If a precondition does not
+// hold or an instruction fails the tree, tags, attributes, quotas and ACLs
+// are reset to what they were before.
+func (fs *FileTreeState) transact(tx *pb.Transaction) error {
+	if tx == nil {
+		return ErrParamsNum
+	}
+	for _, pre := range tx.GetPreconditions() {
+		if err := fs.check(pre); err != nil {
+			return err
+		}
+	}
+	ss := fs.SnapShot()
+	for i, ins := range tx.GetInstructions() {
+		var err error
+		if ins.GetCode() == pb.Instruction_TX {
+			err = ErrNestedTransaction
+		} else {
+			err = fs.Execute(ins)
+		}
+		if err != nil {
+			if err := fs.Unmarshal(strings.NewReader(ss.String())); err != nil {
+				return err
+			}
+			return fmt.Errorf("instruction %d (%s): %w", i, ins.GetCode(), err)
+		}
+	}
+	return nil
+}
+
+func (fs *FileTreeState) check(pre *pb.Precondition) error {
+	p, err := CheckPath(pre.GetPath())
+	if err != nil {
+		return err
+	}
+	fsn, err := mfs.Lookup(fs.root, p)
+	if err != nil && err != os.ErrNotExist {
+		return err
+	}
+	exists := err == nil
+	switch pre.GetKind() {
+	case pb.Precondition_EXISTS:
+		if !exists {
+			return fmt.Errorf("%w: %s does not exist", ErrPreconditionFailed, p)
+		}
+	case pb.Precondition_ABSENT:
+		if exists {
+			return fmt.Errorf("%w: %s exists", ErrPreconditionFailed, p)
+		}
+	case pb.Precondition_CID_EQUALS:
+		if !exists {
+			return fmt.Errorf("%w: %s no longer exists", ErrConflict, p)
+		}
+		nd, err := fsn.GetNode()
+		if err != nil {
+			return err
+		}
+		if got := nd.Cid().String(); got != pre.GetCid() {
+			return fmt.Errorf("%w: %s is %s, not %s", ErrConflict, p, got, pre.GetCid())
+		}
+	default:
+		return fmt.Errorf("unrecognized precondition %d", pre.GetKind())
+	}
+	return nil
+}
+
+func (fs *FileTreeState) setRoot(nd format.Node) error {
+	rootNode, ok := nd.(*merkledag.ProtoNode)
+	if !ok {
+		return errors.New("invalid root node")
+	}
+	r, err := mfs.NewRoot(fs.ctx, fs.dag, rootNode, func(ctx context.Context, cid cid.Cid) error {
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fs.root = r
+	return nil
+}
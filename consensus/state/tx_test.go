@@ -0,0 +1,74 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	fs := newTestState(t)
+	addFile(t, fs, "/a", "a")
+	before := fs.MustGetRoot()
+
+	tx := &pb.Transaction{
+		Preconditions: []*pb.Precondition{
+			{Kind: pb.Precondition_EXISTS, Path: "/a"},
+			{Kind: pb.Precondition_ABSENT, Path: "/b"},
+		},
+		Instructions: []*pb.Instruction{
+			{Code: pb.Instruction_MKDIR, Params: []string{"/dir"}},
+			{Code: pb.Instruction_MV, Params: []string{"/a", "/missing/a"}},
+		},
+	}
+	if err := fs.Execute(&pb.Instruction{Code: pb.Instruction_TX, Tx: tx}); err == nil {
+		t.Fatal("expected the move to fail")
+	}
+	if root := fs.MustGetRoot(); root != before {
+		t.Fatalf("failed transaction changed the root: %s -> %s", before, root)
+	}
+
+	tx.Instructions[1].Params[1] = "/dir/a"
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_TX, Tx: tx})
+	file, err := fs.Stat(context.Background(), "/dir/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := fs.Stat(context.Background(), "/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := &pb.Transaction{
+		Preconditions: []*pb.Precondition{{Kind: pb.Precondition_CID_EQUALS, Path: "/dir", Cid: file.Cid}},
+		Instructions:  []*pb.Instruction{{Code: pb.Instruction_RM, Params: []string{"/dir"}}},
+	}
+	err = fs.Execute(&pb.Instruction{Code: pb.Instruction_TX, Tx: stale})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	stale.Preconditions[0].Cid = st.Cid
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_TX, Tx: stale})
+
+	// side state of the steps before the failing one is rolled back as well
+	addFile(t, fs, "/f", "f")
+	exec(t, fs, &pb.Instruction{Code: pb.Instruction_SETATTR, Params: []string{"/f"}, Attrs: &pb.Attributes{Mode: 0600}})
+	failing := &pb.Transaction{Instructions: []*pb.Instruction{
+		{Code: pb.Instruction_MV, Params: []string{"/f", "/g"}},
+		{Code: pb.Instruction_ACL, Params: []string{"/"}, Acl: &pb.Acl{Principal: "eve", Ops: []string{"*"}}},
+		{Code: pb.Instruction_MV, Params: []string{"/missing", "/h"}},
+	}}
+	if err := fs.Execute(&pb.Instruction{Code: pb.Instruction_TX, Tx: failing}); err == nil {
+		t.Fatal("expected the last move to fail")
+	}
+	if a := fs.Attrs("/f"); a.Mode != 0600 {
+		t.Fatalf("attrs of /f were not restored: %+v", a)
+	}
+	if a := fs.Attrs("/g"); a.Mode != 0 {
+		t.Fatalf("attrs left at /g: %+v", a)
+	}
+	if acls := fs.ACLs(); len(acls) != 0 {
+		t.Fatalf("ACLs left behind: %+v", acls)
+	}
+}
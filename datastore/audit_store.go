@@ -0,0 +1,177 @@
+package datastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditRecord is a change made by the raft entry at Index, and who made it.
+type AuditRecord struct {
+	Index     uint64    `json:"index"`
+	Op        string    `json:"op"`
+	Path      string    `json:"path"`
+	Src       string    `json:"src,omitempty"`
+	Principal string    `json:"principal,omitempty"`
+	Client    string    `json:"client,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// AuditQuery selects the records of changes to Path or below it, all of them
+// when Path is empty or "/", made at or after the raft index Since and the
+// time After. Limit caps the records returned, keeping the oldest when a
+// lower bound is given and the newest otherwise.
+type AuditQuery struct {
+	Path  string
+	Since uint64
+	After time.Time
+	Limit int
+}
+
+// AuditDB indexes audit records by raft index, and by the paths they change
+// so that the history of a subtree can be read without a full scan.
+type AuditDB struct {
+	db *BadgerDB
+}
+
+// Add stores the records of the entry at index, adding them again after a
+// restart replays the entry overwrites them.
+func (a *AuditDB) Add(index uint64, records []AuditRecord) error {
+	for i, r := range records {
+		r.Index = index
+		val, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		key := a.key(index, uint16(i))
+		if err := a.db.Set(key, val); err != nil {
+			return err
+		}
+		for _, p := range []string{r.Path, r.Src} {
+			if !strings.HasPrefix(p, "/") {
+				continue
+			}
+			if p != "/" {
+				p = strings.TrimSuffix(p, "/")
+			}
+			if err := a.db.Set(a.pathKey(p, key), key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *AuditDB) Query(q AuditQuery) ([]AuditRecord, error) {
+	bounded := q.Since > 0 || !q.After.IsZero()
+	match := func(r AuditRecord) bool {
+		return r.Index >= q.Since && !r.Time.Before(q.After)
+	}
+	path := strings.TrimSuffix(q.Path, "/")
+	if path == "" {
+		return a.scan(bounded, q, match)
+	}
+	var keys [][]byte
+	prefix := append([]byte{a.pathPrefix()}, path...)
+	err := a.db.Iterate(prefix, func(key, val []byte) error {
+		p := string(key[1:bytes.IndexByte(key, 0)])
+		if p == path || strings.HasPrefix(p, path+"/") {
+			keys = append(keys, val)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	records := make([]AuditRecord, 0)
+	for i, key := range keys {
+		if i > 0 && bytes.Equal(key, keys[i-1]) {
+			continue
+		}
+		val, err := a.db.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		r := AuditRecord{}
+		if err := json.Unmarshal(val, &r); err != nil {
+			return nil, err
+		}
+		if match(r) {
+			records = append(records, r)
+		}
+	}
+	if q.Limit > 0 && len(records) > q.Limit {
+		if bounded {
+			records = records[:q.Limit]
+		} else {
+			records = records[len(records)-q.Limit:]
+		}
+	}
+	return records, nil
+}
+
+// scan reads the records in index order, forward from the lower bound or
+// backward from the newest one.
+func (a *AuditDB) scan(bounded bool, q AuditQuery, match func(AuditRecord) bool) ([]AuditRecord, error) {
+	records := make([]AuditRecord, 0)
+	start := a.key(q.Since, 0)
+	if !bounded {
+		start = append(a.key(^uint64(0), ^uint16(0)), 0xff)
+	}
+	err := a.db.Seek([]byte{a.prefix()}, start, !bounded, func(key, val []byte) error {
+		r := AuditRecord{}
+		if err := json.Unmarshal(val, &r); err != nil {
+			return err
+		}
+		if match(r) {
+			records = append(records, r)
+		}
+		if q.Limit > 0 && len(records) >= q.Limit {
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !bounded {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+	return records, nil
+}
+
+func (a *AuditDB) key(index uint64, seq uint16) []byte {
+	key := make([]byte, 11)
+	key[0] = a.prefix()
+	binary.BigEndian.PutUint64(key[1:], index)
+	binary.BigEndian.PutUint16(key[9:], seq)
+	return key
+}
+
+// pathKey orders the path index by path, then by the record key.
+func (a *AuditDB) pathKey(path string, key []byte) []byte {
+	k := make([]byte, 0, 2+len(path)+len(key))
+	k = append(k, a.pathPrefix())
+	k = append(k, path...)
+	k = append(k, 0)
+	return append(k, key...)
+}
+
+func (a *AuditDB) prefix() byte {
+	return 'a'
+}
+
+func (a *AuditDB) pathPrefix() byte {
+	return 'A'
+}
+
+func NewAuditDB(db *BadgerDB) *AuditDB {
+	return &AuditDB{db}
+}
@@ -0,0 +1,68 @@
+package datastore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAuditDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := NewBadgerStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	a := NewAuditDB(db)
+	start := time.Now().Add(-time.Hour)
+	entries := [][]AuditRecord{
+		{{Op: "mkdir", Path: "/team/"}},
+		{{Op: "cp", Path: "/team/a", Src: "QmA", Principal: "ana"}},
+		{{Op: "mv", Path: "/other/a", Src: "/team/a"}, {Op: "mkdir", Path: "/team-b"}},
+	}
+	for i, records := range entries {
+		for j := range records {
+			records[j].Time = start.Add(time.Duration(i) * time.Minute)
+		}
+		if err := a.Add(uint64(i+1), records); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cases := []struct {
+		q    AuditQuery
+		want []uint64
+	}{
+		{AuditQuery{}, []uint64{1, 2, 3, 3}},
+		{AuditQuery{Path: "/team"}, []uint64{1, 2, 3}},
+		{AuditQuery{Path: "/team/", Since: 2}, []uint64{2, 3}},
+		{AuditQuery{Path: "/other"}, []uint64{3}},
+		{AuditQuery{After: start.Add(time.Second * 30)}, []uint64{2, 3, 3}},
+		{AuditQuery{Limit: 2}, []uint64{3, 3}},
+		{AuditQuery{Path: "/team", Since: 1, Limit: 1}, []uint64{1}},
+	}
+	for i, tc := range cases {
+		records, err := a.Query(tc.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]uint64, len(records))
+		for j, r := range records {
+			got[j] = r.Index
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("case %d: got %v, want %v", i, got, tc.want)
+			continue
+		}
+		for j := range got {
+			if got[j] != tc.want[j] {
+				t.Errorf("case %d: got %v, want %v", i, got, tc.want)
+				break
+			}
+		}
+	}
+}
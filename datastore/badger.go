@@ -8,6 +8,7 @@ import (
 var (
 	dbStateKey     = []byte("state")
 	ErrKeyNotFound = errors.New("not found")
+	ErrStop        = errors.New("stop iteration")
 )
 
 type BadgerDB struct {
@@ -36,6 +37,12 @@ func (s *BadgerDB) Close() error {
 	return s.db.Close()
 }
 
+// Size returns the bytes on disk of the LSM tree and of the value log, as
+// last computed by badger.
+func (s *BadgerDB) Size() (lsm, vlog int64) {
+	return s.db.Size()
+}
+
 func (s *BadgerDB) Set(key []byte, val []byte) error {
 	tx := s.db.NewTransaction(true)
 	defer tx.Discard()
@@ -58,6 +65,50 @@ func (s *BadgerDB) Get(key []byte) ([]byte, error) {
 	return item.ValueCopy(nil)
 }
 
+func (s *BadgerDB) Iterate(prefix []byte, fn func(key, val []byte) error) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err := fn(item.KeyCopy(nil), val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Seek iterates the keys with prefix starting at start, in descending order
+// when reverse is set. fn stops the iteration by returning ErrStop.
+func (s *BadgerDB) Seek(prefix, start []byte, reverse bool, fn func(key, val []byte) error) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = reverse
+		opts.Prefix = prefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err := fn(item.KeyCopy(nil), val); err != nil {
+				if err == ErrStop {
+					return nil
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *BadgerDB) NewTransaction(update bool) Transaction {
 	return &Txn{s.db.NewTransaction(update)}
 }
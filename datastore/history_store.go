@@ -0,0 +1,172 @@
+package datastore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+)
+
+// Version is the root of the file tree after the raft entry at Index was
+// applied.
+type Version struct {
+	Index uint64    `json:"index"`
+	Root  string    `json:"root"`
+	Time  time.Time `json:"time"`
+	// Attrs are the attributes of the tree entries at Root, as JSON. They
+	// are only recorded when they changed, see AttrsAt.
+	Attrs json.RawMessage `json:"attrs,omitempty"`
+}
+
+type HistoryDB struct {
+	db *BadgerDB
+}
+
+func (h *HistoryDB) Add(v Version) error {
+	val, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return h.db.Set(h.key(v.Index), val)
+}
+
+// At returns the version in effect at index, that is the latest one recorded
+// at or before it.
+func (h *HistoryDB) At(index uint64) (Version, error) {
+	return h.find(h.key(index), func(Version) bool { return true })
+}
+
+// Before returns the latest version recorded at or before t.
+func (h *HistoryDB) Before(t time.Time) (Version, error) {
+	return h.find(h.end(), func(v Version) bool {
+		return !v.Time.After(t)
+	})
+}
+
+// ByRoot returns the latest version whose root is root.
+func (h *HistoryDB) ByRoot(root string) (Version, error) {
+	return h.find(h.end(), func(v Version) bool {
+		return v.Root == root
+	})
+}
+
+// Versions lists up to limit versions, newest first. A limit of zero lists
+// them all.
+func (h *HistoryDB) Versions(limit int) ([]Version, error) {
+	versions := make([]Version, 0)
+	err := h.db.Seek([]byte{h.prefix()}, h.end(), true, func(key, val []byte) error {
+		v := Version{}
+		if err := json.Unmarshal(val, &v); err != nil {
+			return err
+		}
+		versions = append(versions, v)
+		if limit > 0 && len(versions) >= limit {
+			return ErrStop
+		}
+		return nil
+	})
+	return versions, err
+}
+
+// AttrsAt returns the attributes in effect at index, recorded with the latest
+// version at or before it that has them.
+func (h *HistoryDB) AttrsAt(index uint64) (json.RawMessage, error) {
+	v, err := h.find(h.key(index), func(v Version) bool {
+		return len(v.Attrs) > 0
+	})
+	if err == ErrKeyNotFound {
+		return nil, nil
+	}
+	return v.Attrs, err
+}
+
+// Prune removes the versions beyond the newest keep ones and those older
+// than maxAge, unless protect returns true for them, and returns them. Zero
+// disables the respective limit. Attributes of a removed version that later
+// ones still rely on move to the oldest of those kept.
+func (h *HistoryDB) Prune(keep int, maxAge time.Duration, protect func(Version) bool) ([]Version, error) {
+	var stale, inherit []Version
+	// needy is the oldest kept version seen since the last one with
+	// attributes, it falls back on older ones
+	var needy *Version
+	n := 0
+	err := h.db.Seek([]byte{h.prefix()}, h.end(), true, func(key, val []byte) error {
+		v := Version{}
+		if err := json.Unmarshal(val, &v); err != nil {
+			return err
+		}
+		n++
+		expired := (keep > 0 && n > keep) || (maxAge > 0 && time.Since(v.Time) > maxAge)
+		if !expired || (protect != nil && protect(v)) {
+			if len(v.Attrs) > 0 {
+				needy = nil
+			} else {
+				needy = &v
+			}
+			return nil
+		}
+		stale = append(stale, v)
+		if len(v.Attrs) > 0 && needy != nil {
+			needy.Attrs = v.Attrs
+			inherit = append(inherit, *needy)
+			needy = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range inherit {
+		if err := h.Add(v); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range stale {
+		if err := h.db.Delete(h.key(v.Index)); err != nil {
+			return nil, err
+		}
+	}
+	return stale, nil
+}
+
+// find returns the newest version at or before start that matches.
+func (h *HistoryDB) find(start []byte, match func(Version) bool) (Version, error) {
+	found := Version{}
+	err := h.db.Seek([]byte{h.prefix()}, start, true, func(key, val []byte) error {
+		v := Version{}
+		if err := json.Unmarshal(val, &v); err != nil {
+			return err
+		}
+		if match(v) {
+			found = v
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return Version{}, err
+	}
+	if found.Root == "" {
+		return Version{}, ErrKeyNotFound
+	}
+	return found, nil
+}
+
+func (h *HistoryDB) key(index uint64) []byte {
+	key := make([]byte, 9)
+	key[0] = h.prefix()
+	binary.BigEndian.PutUint64(key[1:], index)
+	return key
+}
+
+// end sorts after every key of the history.
+func (h *HistoryDB) end() []byte {
+	return append(h.key(^uint64(0)), 0xff)
+}
+
+func (h *HistoryDB) prefix() byte {
+	return 'h'
+}
+
+func NewHistoryDB(db *BadgerDB) *HistoryDB {
+	return &HistoryDB{db}
+}
@@ -0,0 +1,62 @@
+package datastore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHistoryDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := NewBadgerStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	h := NewHistoryDB(db)
+	start := time.Now().Add(-time.Hour)
+	for i, root := range []string{"QmA", "QmB", "QmC"} {
+		v := Version{Index: uint64(i*10 + 10), Root: root, Time: start.Add(time.Duration(i) * time.Minute)}
+		if root == "QmB" {
+			v.Attrs = []byte(`{"/a":{"mode":420}}`)
+		}
+		if err := h.Add(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, err := h.At(25); err != nil || v.Root != "QmB" {
+		t.Fatalf("At(25) = %v, %v", v, err)
+	}
+	if _, err := h.At(5); err != ErrKeyNotFound {
+		t.Fatalf("At(5) should not exist, got %v", err)
+	}
+	if v, err := h.Before(start.Add(time.Second * 30)); err != nil || v.Root != "QmA" {
+		t.Fatalf("Before = %v, %v", v, err)
+	}
+	if v, err := h.ByRoot("QmC"); err != nil || v.Index != 30 {
+		t.Fatalf("ByRoot = %v, %v", v, err)
+	}
+	if attrs, err := h.AttrsAt(35); err != nil || string(attrs) != `{"/a":{"mode":420}}` {
+		t.Fatalf("AttrsAt(35) = %s, %v", attrs, err)
+	}
+	pruned, err := h.Prune(1, 0, func(v Version) bool { return v.Root == "QmA" })
+	if err != nil || len(pruned) != 1 || pruned[0].Root != "QmB" {
+		t.Fatalf("Prune = %v, %v", pruned, err)
+	}
+	// the attributes of the pruned version moved to the next one
+	if attrs, err := h.AttrsAt(30); err != nil || string(attrs) != `{"/a":{"mode":420}}` {
+		t.Fatalf("AttrsAt(30) after prune = %s, %v", attrs, err)
+	}
+	versions, err := h.Versions(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Root != "QmC" || versions[1].Root != "QmA" {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+}
@@ -0,0 +1,37 @@
+package datastore
+
+type PinDB struct {
+	db *BadgerDB
+}
+
+func (p *PinDB) Pins() (map[string]string, error) {
+	pins := make(map[string]string)
+	err := p.db.Iterate([]byte{p.prefix()}, func(key, val []byte) error {
+		pins[string(key[1:])] = string(val)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
+
+func (p *PinDB) AddPin(c string, mode string) error {
+	return p.db.Set(p.key(c), []byte(mode))
+}
+
+func (p *PinDB) RemovePin(c string) error {
+	return p.db.Delete(p.key(c))
+}
+
+func (p *PinDB) key(c string) []byte {
+	return append([]byte{p.prefix()}, c...)
+}
+
+func (p *PinDB) prefix() byte {
+	return 'p'
+}
+
+func NewPinDB(db *BadgerDB) *PinDB {
+	return &PinDB{db}
+}
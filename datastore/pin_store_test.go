@@ -0,0 +1,40 @@
+package datastore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestPinDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pin-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := NewBadgerStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.StoreState("{}"); err != nil {
+		t.Fatal(err)
+	}
+	pins := NewPinDB(db)
+	if err := pins.AddPin("QmA", "direct"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pins.AddPin("QmB", "recursive"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pins.RemovePin("QmA"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := pins.Pins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got["QmB"] != "recursive" {
+		t.Fatalf("unexpected pins: %v", got)
+	}
+}
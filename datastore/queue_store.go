@@ -0,0 +1,31 @@
+package datastore
+
+// QueueDB persists the items of a delivery queue, keys are kept in byte
+// order so items come back in the order they were queued if their keys are
+// big endian sequence numbers.
+type QueueDB struct {
+	db     *BadgerDB
+	prefix byte
+}
+
+func (q *QueueDB) Put(key, val []byte) error {
+	return q.db.Set(q.key(key), val)
+}
+
+func (q *QueueDB) Delete(key []byte) error {
+	return q.db.Delete(q.key(key))
+}
+
+func (q *QueueDB) Items(fn func(key, val []byte) error) error {
+	return q.db.Iterate([]byte{q.prefix}, func(key, val []byte) error {
+		return fn(key[1:], val)
+	})
+}
+
+func (q *QueueDB) key(k []byte) []byte {
+	return append([]byte{q.prefix}, k...)
+}
+
+func NewWebhookDB(db *BadgerDB) *QueueDB {
+	return &QueueDB{db: db, prefix: 'w'}
+}
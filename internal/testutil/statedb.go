@@ -0,0 +1,21 @@
+// Package testutil holds helpers shared by the tests of several packages.
+package testutil
+
+import "github.com/icetrays/icetrays/datastore"
+
+// MemStateDB is a datastore.StateDB that keeps the state in memory.
+type MemStateDB struct {
+	state string
+}
+
+func (m *MemStateDB) StoreState(s string) error {
+	m.state = s
+	return nil
+}
+
+func (m *MemStateDB) LoadState() (string, error) {
+	if m.state == "" {
+		return "", datastore.ErrKeyNotFound
+	}
+	return m.state, nil
+}
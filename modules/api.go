@@ -0,0 +1,837 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/icetrays/icetrays/auth"
+	"github.com/icetrays/icetrays/consensus"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/consensus/state"
+	"github.com/icetrays/icetrays/pinning"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/interface-go-ipfs-core/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	gopath "path"
+	"strconv"
+	"time"
+)
+
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type Entry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	Hash string `json:"hash"`
+	state.Attrs
+}
+
+type LsResponse struct {
+	Path    string  `json:"path"`
+	Entries []Entry `json:"entries"`
+}
+
+// Expect makes a mutation conditional. Root is the root CID the client last
+// read, Path and Cid expect a CID at a path instead. A mutation whose
+// expectation no longer holds fails with 409 and code Aborted. Mutations
+// without a body take them as query parameters.
+type Expect struct {
+	Root string `json:"expect_root" form:"expect_root"`
+	Path string `json:"expect_path" form:"expect_path"`
+	Cid  string `json:"expect_cid" form:"expect_cid"`
+}
+
+type CpRequest struct {
+	// Src is an absolute path in the tree or the CID of existing content. A
+	// path with glob patterns copies every match into the directory Dst.
+	Src string `json:"src" binding:"required"`
+	Dst string `json:"dst" binding:"required"`
+	// Parents creates the missing parent directories of Dst.
+	Parents bool `json:"parents"`
+	Expect
+}
+
+// MvRequest moves Src to Dst, a Src with glob patterns moves every match into
+// the directory Dst.
+type MvRequest struct {
+	Src string `json:"src" binding:"required"`
+	Dst string `json:"dst" binding:"required"`
+	Expect
+}
+
+// RmOptions are the query parameters of DELETE /v1/files, the path may
+// contain glob patterns. Non empty directories are only removed with
+// Recursive, and Force ignores paths that do not exist.
+type RmOptions struct {
+	Recursive bool `form:"recursive"`
+	Force     bool `form:"force"`
+}
+
+type MkdirRequest struct {
+	Path string `json:"path" binding:"required"`
+	Expect
+}
+
+// SymlinkRequest creates a symlink at Path. Target is stored as given, a
+// relative one is resolved from the directory of Path when the link is read.
+type SymlinkRequest struct {
+	Path   string `json:"path" binding:"required"`
+	Target string `json:"target" binding:"required"`
+	Expect
+}
+
+// SetattrRequest sets the attributes of Path. Mode is octal like "0644",
+// Mtime is RFC 3339, and Metadata is merged into the user metadata of Path,
+// an empty value removes its key. Omitted attributes are left as they are.
+type SetattrRequest struct {
+	Path     string            `json:"path" binding:"required"`
+	Mode     string            `json:"mode"`
+	Mtime    *time.Time        `json:"mtime"`
+	Metadata map[string]string `json:"metadata"`
+	Expect
+}
+
+// QuotaRequest limits the directory Path, zero leaves a limit unset and a
+// request without limits removes the quota. Operations that would grow the
+// directory past a limit fail with 507 and code ResourceExhausted.
+type QuotaRequest struct {
+	Path       string `json:"path" binding:"required"`
+	MaxBytes   uint64 `json:"max_bytes"`
+	MaxEntries uint64 `json:"max_entries"`
+	Expect
+}
+
+// ACLRequest allows Principal the Ops, instruction names such as cp, mv or
+// rm or * for all of them, under Prefix. A request without ops removes the
+// entry. Once a principal has ACLs, changes outside of them fail with 403
+// and code PermissionDenied.
+type ACLRequest struct {
+	Principal string   `json:"principal" binding:"required"`
+	Prefix    string   `json:"prefix" binding:"required"`
+	Ops       []string `json:"ops"`
+	Expect
+}
+
+// RestoreRequest puts back the node at Path as it was in the version At, or
+// the node Cid when given instead. Path "/" rolls back the whole tree. The
+// attributes under Path are replaced by those of the version, a Cid leaves
+// none.
+type RestoreRequest struct {
+	Path string `json:"path" binding:"required"`
+	At   string `json:"at"`
+	Cid  string `json:"cid"`
+	Expect
+}
+
+// TagRequest names the current root of the tree, see GET /v1/tags.
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// TxOp is one step of a transaction, src and dst are used by cp and mv,
+// path by rm and mkdir, path and target by symlink. The flags mean the same
+// as for the single ops.
+type TxOp struct {
+	Op        string `json:"op" binding:"required"`
+	Src       string `json:"src"`
+	Dst       string `json:"dst"`
+	Path      string `json:"path"`
+	Target    string `json:"target"`
+	Parents   bool   `json:"parents"`
+	Recursive bool   `json:"recursive"`
+	Force     bool   `json:"force"`
+}
+
+// Precondition is checked before a transaction runs. Type is one of exists,
+// absent and cid_equals, the latter compares the CID at Path with Cid.
+type Precondition struct {
+	Type string `json:"type" binding:"required"`
+	Path string `json:"path" binding:"required"`
+	Cid  string `json:"cid"`
+}
+
+type TxRequest struct {
+	Preconditions []Precondition `json:"preconditions"`
+	Ops           []TxOp         `json:"ops" binding:"required,min=1"`
+}
+
+type TxResponse struct {
+	Ops int `json:"ops"`
+}
+
+type WriteResponse struct {
+	Path string `json:"path"`
+	Cid  string `json:"cid"`
+}
+
+type ReadlinkResponse struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+}
+
+type OpResponse struct {
+	Op     string   `json:"op"`
+	Params []string `json:"params"`
+}
+
+type API struct {
+	node    *consensus.Node
+	tracker *pinning.Tracker
+	feed    *consensus.Feed
+	history *consensus.History
+	auth    *auth.Authenticator
+	audits  *consensus.Audit
+}
+
+func (api *API) Register(router gin.IRouter) {
+	v1 := router.Group("/v1", origin)
+	read := v1.Group("", api.allow(auth.Reader))
+	write := v1.Group("", api.allow(auth.Writer))
+	admin := v1.Group("", api.allow(auth.Admin))
+
+	read.GET("/ls/*path", api.ls)
+	read.GET("/stat/*path", api.stat)
+	read.GET("/tree/*path", api.tree)
+	read.GET("/cat/*path", api.cat)
+	read.GET("/readlink/*path", api.readlink)
+	write.POST("/cp", api.cp)
+	write.POST("/mv", api.mv)
+	write.PUT("/files/*path", api.write)
+	write.DELETE("/files/*path", api.rm)
+	write.POST("/mkdir", api.mkdir)
+	write.POST("/symlink", api.symlink)
+	write.POST("/setattr", api.setattr)
+	admin.POST("/quota", api.quota)
+	admin.POST("/acl", api.acl)
+	admin.GET("/acls", api.acls)
+	admin.GET("/audit", api.audit)
+	read.GET("/quotas", api.quotas)
+	read.GET("/usage/*path", api.usage)
+	write.POST("/tx", api.tx)
+	write.POST("/restore", api.restore)
+	read.GET("/tags", api.tags)
+	write.POST("/tags", api.tag)
+	write.DELETE("/tags/:name", api.untag)
+
+	read.GET("/status", api.status)
+	read.GET("/peers", api.peers)
+	admin.POST("/peers/:id/voter", api.membership("add_voter", api.node.AddVoter))
+	admin.POST("/peers/:id/nonvoter", api.membership("add_nonvoter", api.node.AddNonVoter))
+	admin.POST("/peers/:id/demote", api.membership("demote_voter", api.node.DemoteVoter))
+	admin.DELETE("/peers/:id", api.membership("remove_peer", api.node.RemovePeer))
+
+	read.GET("/pins", api.pins)
+	read.GET("/pins/:cid", api.pins)
+
+	read.GET("/events", api.events)
+	read.GET("/history", api.versions)
+}
+
+func (api *API) ls(c *gin.Context) {
+	path := c.Param("path")
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	listing, err := st.Ls(c, path)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	attrs, err := st.LsAttrs(path)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	types, err := st.LsTypes(c, path)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	res := LsResponse{
+		Path:    path,
+		Entries: make([]Entry, len(listing)),
+	}
+	for i, l := range listing {
+		res.Entries[i] = Entry{
+			Name:  l.Name,
+			Type:  types[l.Name],
+			Size:  l.Size,
+			Hash:  l.Hash,
+			Attrs: attrs[l.Name],
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (api *API) stat(c *gin.Context) {
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	stat, err := st.Stat(c, c.Param("path"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stat)
+}
+
+func (api *API) tree(c *gin.Context) {
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "-1"))
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	t, err := st.Tree(c, c.Param("path"), depth)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// cat serves the content of a file, honouring Range and If-Range headers.
+func (api *API) cat(c *gin.Context) {
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	path := c.Param("path")
+	r, err := st.Open(c, path)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	defer r.Close()
+	http.ServeContent(c.Writer, c.Request, gopath.Base(path), time.Time{}, r)
+}
+
+func (api *API) cp(c *gin.Context) {
+	req := CpRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	api.op(c, "cp", req.Expect, &pb.Flags{Parents: req.Parents}, pb.Instruction_CP, req.Dst, req.Src)
+}
+
+func (api *API) mv(c *gin.Context) {
+	req := MvRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	api.op(c, "mv", req.Expect, &pb.Flags{}, pb.Instruction_MV, req.Src, req.Dst)
+}
+
+// write streams the request body into the IPFS node and copies it to path.
+// The chunker and raw-leaves queries are passed to the unixfs importer.
+func (api *API) write(c *gin.Context) {
+	path := c.Param("path")
+	var opts []options.UnixfsAddOption
+	if chunker := c.Query("chunker"); chunker != "" {
+		opts = append(opts, options.Unixfs.Chunker(chunker))
+	}
+	if raw := c.Query("raw-leaves"); raw != "" {
+		enable, err := strconv.ParseBool(raw)
+		if err != nil {
+			abort(c, invalid(err))
+			return
+		}
+		opts = append(opts, options.Unixfs.RawLeaves(enable))
+	}
+	expect := Expect{}
+	if err := c.ShouldBindQuery(&expect); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	preconditions, err := expect.preconditions()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	id, err := api.node.WriteIf(c, preconditions, path, c.Request.Body, opts...)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WriteResponse{Path: path, Cid: id.String()})
+}
+
+func (api *API) rm(c *gin.Context) {
+	path := c.Param("path")
+	if path == "/" {
+		abort(c, invalid(errors.New("cannot remove the root directory")))
+		return
+	}
+	expect := Expect{}
+	if err := c.ShouldBindQuery(&expect); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	opts := RmOptions{}
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	api.op(c, "rm", expect, &pb.Flags{Recursive: opts.Recursive, Force: opts.Force}, pb.Instruction_RM, path)
+}
+
+func (api *API) mkdir(c *gin.Context) {
+	req := MkdirRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	api.op(c, "mkdir", req.Expect, nil, pb.Instruction_MKDIR, req.Path)
+}
+
+func (api *API) symlink(c *gin.Context) {
+	req := SymlinkRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	api.op(c, "symlink", req.Expect, nil, pb.Instruction_SYMLINK, req.Path, req.Target)
+}
+
+func (api *API) setattr(c *gin.Context) {
+	req := SetattrRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	attrs := &pb.Attributes{Metadata: req.Metadata}
+	if req.Mode != "" {
+		mode, err := strconv.ParseUint(req.Mode, 8, 32)
+		if err != nil || mode == 0 {
+			abort(c, invalid(fmt.Errorf("mode %q is not a non zero octal number", req.Mode)))
+			return
+		}
+		attrs.Mode = uint32(mode)
+	}
+	if req.Mtime != nil {
+		attrs.Mtime = req.Mtime.UnixNano()
+	}
+	preconditions, err := req.Expect.preconditions()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	ins := &pb.Instruction{Code: pb.Instruction_SETATTR, Params: []string{req.Path}, Attrs: attrs}
+	if err := api.node.ExecIf(c, preconditions, ins); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: "setattr", Params: []string{req.Path}})
+}
+
+func (api *API) quota(c *gin.Context) {
+	req := QuotaRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	preconditions, err := req.Expect.preconditions()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	ins := &pb.Instruction{
+		Code:   pb.Instruction_QUOTA,
+		Params: []string{req.Path},
+		Quota:  &pb.Quota{MaxBytes: req.MaxBytes, MaxEntries: req.MaxEntries},
+	}
+	if err := api.node.ExecIf(c, preconditions, ins); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: "quota", Params: []string{req.Path}})
+}
+
+func (api *API) acl(c *gin.Context) {
+	req := ACLRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	preconditions, err := req.Expect.preconditions()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	ins := &pb.Instruction{
+		Code:   pb.Instruction_ACL,
+		Params: []string{req.Prefix},
+		Acl:    &pb.Acl{Principal: req.Principal, Ops: req.Ops},
+	}
+	if err := api.node.ExecIf(c, preconditions, ins); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: "acl", Params: []string{req.Principal, req.Prefix}})
+}
+
+func (api *API) acls(c *gin.Context) {
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, st.ACLs())
+}
+
+func (api *API) quotas(c *gin.Context) {
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	usages, err := st.Quotas(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, usages)
+}
+
+func (api *API) usage(c *gin.Context) {
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	u, err := st.Usage(c, c.Param("path"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
+
+func (api *API) readlink(c *gin.Context) {
+	path := c.Param("path")
+	st, err := api.readState(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	target, err := st.Readlink(path)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ReadlinkResponse{Path: path, Target: target})
+}
+
+func (api *API) restore(c *gin.Context) {
+	req := RestoreRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	if (req.At == "") == (req.Cid == "") {
+		abort(c, invalid(errors.New("exactly one of at and cid is required")))
+		return
+	}
+	id := req.Cid
+	var attrs map[string]*pb.Attributes
+	if req.At != "" {
+		view, err := api.history.View(c.Request.Context(), req.At)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		st, err := view.Stat(c, req.Path)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		id = st.Cid
+		if attrs, err = view.Subtree(req.Path); err != nil {
+			abort(c, err)
+			return
+		}
+	} else if _, err := cid.Decode(id); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	preconditions, err := req.Expect.preconditions()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	params := []string{req.Path, id}
+	ins := &pb.Instruction{Code: pb.Instruction_RESTORE, Params: params, Restored: attrs}
+	if err := api.node.ExecIf(c, preconditions, ins); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: "restore", Params: params})
+}
+
+func (api *API) tags(c *gin.Context) {
+	consistency, err := readConsistency(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	tags, err := api.node.Tags(c, consistency)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+func (api *API) tag(c *gin.Context) {
+	req := TagRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	if err := api.node.Tag(c, req.Name); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: "tag", Params: []string{req.Name}})
+}
+
+func (api *API) untag(c *gin.Context) {
+	name := c.Param("name")
+	if err := api.node.Untag(c, name); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: "untag", Params: []string{name}})
+}
+
+func (api *API) tx(c *gin.Context) {
+	req := TxRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	tx, err := req.transaction()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	if err := api.node.Transact(c, tx); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, TxResponse{Ops: len(tx.Instructions)})
+}
+
+func (e Expect) preconditions() ([]*pb.Precondition, error) {
+	var preconditions []*pb.Precondition
+	if e.Root != "" {
+		root, err := cid.Decode(e.Root)
+		if err != nil {
+			return nil, fmt.Errorf("expect_root: %w", err)
+		}
+		preconditions = append(preconditions, &pb.Precondition{Kind: pb.Precondition_CID_EQUALS, Path: "/", Cid: root.String()})
+	}
+	if (e.Path == "") != (e.Cid == "") {
+		return nil, errors.New("expect_path and expect_cid must be given together")
+	}
+	if e.Path != "" {
+		c, err := cid.Decode(e.Cid)
+		if err != nil {
+			return nil, fmt.Errorf("expect_cid: %w", err)
+		}
+		preconditions = append(preconditions, &pb.Precondition{Kind: pb.Precondition_CID_EQUALS, Path: e.Path, Cid: c.String()})
+	}
+	return preconditions, nil
+}
+
+var preconditionKinds = map[string]pb.Precondition_Kind{
+	"exists":     pb.Precondition_EXISTS,
+	"absent":     pb.Precondition_ABSENT,
+	"cid_equals": pb.Precondition_CID_EQUALS,
+}
+
+func (req *TxRequest) transaction() (*pb.Transaction, error) {
+	tx := &pb.Transaction{}
+	for _, pre := range req.Preconditions {
+		kind, ok := preconditionKinds[pre.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown precondition %q", pre.Type)
+		}
+		if kind == pb.Precondition_CID_EQUALS && pre.Cid == "" {
+			return nil, errors.New("cid_equals needs a cid")
+		}
+		tx.Preconditions = append(tx.Preconditions, &pb.Precondition{Kind: kind, Path: pre.Path, Cid: pre.Cid})
+	}
+	for i, op := range req.Ops {
+		ins := &pb.Instruction{}
+		switch op.Op {
+		case "cp":
+			ins.Code, ins.Params = pb.Instruction_CP, []string{op.Dst, op.Src}
+			ins.Flags = &pb.Flags{Parents: op.Parents}
+		case "mv":
+			ins.Code, ins.Params = pb.Instruction_MV, []string{op.Src, op.Dst}
+			ins.Flags = &pb.Flags{}
+		case "rm":
+			ins.Code, ins.Params = pb.Instruction_RM, []string{op.Path}
+			ins.Flags = &pb.Flags{Recursive: op.Recursive, Force: op.Force}
+		case "mkdir":
+			ins.Code, ins.Params = pb.Instruction_MKDIR, []string{op.Path}
+		case "symlink":
+			ins.Code, ins.Params = pb.Instruction_SYMLINK, []string{op.Path, op.Target}
+		default:
+			return nil, fmt.Errorf("op %d: unknown op %q", i, op.Op)
+		}
+		for _, p := range ins.Params {
+			if p == "" {
+				return nil, fmt.Errorf("op %d: %s is missing a parameter", i, op.Op)
+			}
+		}
+		tx.Instructions = append(tx.Instructions, ins)
+	}
+	return tx, nil
+}
+
+func (api *API) op(c *gin.Context, name string, expect Expect, flags *pb.Flags, code pb.Instruction_Code, params ...string) {
+	preconditions, err := expect.preconditions()
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	ins := &pb.Instruction{Code: code, Params: params, Flags: flags}
+	if err := api.node.ExecIf(c, preconditions, ins); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, OpResponse{Op: name, Params: params})
+}
+
+func (api *API) status(c *gin.Context) {
+	c.JSON(http.StatusOK, api.node.Status())
+}
+
+func (api *API) peers(c *gin.Context) {
+	peers, err := api.node.Peers()
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, peers)
+}
+
+func (api *API) membership(name string, change func(ctx context.Context, id string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if err := change(c, id); err != nil {
+			abort(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, OpResponse{Op: name, Params: []string{id}})
+	}
+}
+
+func (api *API) pins(c *gin.Context) {
+	id := c.Param("cid")
+	if id != "" {
+		parsed, err := cid.Decode(id)
+		if err != nil {
+			abort(c, invalid(err))
+			return
+		}
+		id = parsed.String()
+	}
+	report, err := api.tracker.Status(c, id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// readState returns the version of the tree a read asks for with ?at=, or the
+// current one with the consistency of ?consistency=.
+func (api *API) readState(c *gin.Context) (*state.FileTreeState, error) {
+	if at := c.Query("at"); at != "" {
+		return api.history.View(c.Request.Context(), at)
+	}
+	consistency, err := readConsistency(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.node.ReadState(c, consistency)
+}
+
+func (api *API) versions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	versions, err := api.history.Versions(limit)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// readConsistency reads the ?consistency= query, reads are stale by default.
+func readConsistency(c *gin.Context) (consensus.Consistency, error) {
+	return consensus.ParseConsistency(c.Query("consistency"))
+}
+
+func invalid(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func abort(c *gin.Context, err error) {
+	code := consensus.ErrorCode(err)
+	c.AbortWithStatusJSON(httpStatus(code), ErrorResponse{
+		Code:    code.String(),
+		Message: status.Convert(err).Message(),
+	})
+}
+
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.ResourceExhausted:
+		return http.StatusInsufficientStorage
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/icetrays/icetrays/auth"
+	"github.com/icetrays/icetrays/consensus/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAbortStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{state.ErrParamsNum, http.StatusBadRequest},
+		{fmt.Errorf("lookup: %w", os.ErrNotExist), http.StatusNotFound},
+		{status.Error(codes.NotFound, "file does not exist"), http.StatusNotFound},
+		{status.Error(codes.Unavailable, "leadership lost"), http.StatusServiceUnavailable},
+		{fmt.Errorf("instruction 0 (RM): %w", state.ErrConflict), http.StatusConflict},
+		{fmt.Errorf("%w: /team", state.ErrQuotaExceeded), http.StatusInsufficientStorage},
+		{fmt.Errorf("boom"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		abort(c, tc.err)
+		if w.Code != tc.want {
+			t.Errorf("%v: got status %d, want %d", tc.err, w.Code, tc.want)
+		}
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := &API{}
+	api.Register(router)
+	cases := []struct {
+		method, path, body string
+	}{
+		{"POST", "/v1/cp", `{"src": "/a"}`},
+		{"POST", "/v1/mv", `not json`},
+		{"POST", "/v1/mkdir", `{}`},
+		{"DELETE", "/v1/files/", ``},
+		{"GET", "/v1/pins/not-a-cid", ``},
+		{"GET", "/v1/ls/?consistency=eventual", ``},
+		{"GET", "/v1/stat/a?consistency=strong", ``},
+		{"PUT", "/v1/files/a?raw-leaves=maybe", `data`},
+		{"POST", "/v1/mkdir", `{"path": "/a", "expect_root": "not-a-cid"}`},
+		{"POST", "/v1/mv", `{"src": "/a", "dst": "/b", "expect_path": "/a"}`},
+		{"DELETE", "/v1/files/a?expect_root=nope", ``},
+		{"DELETE", "/v1/files/a?recursive=maybe", ``},
+		{"POST", "/v1/restore", `{"path": "/"}`},
+		{"POST", "/v1/tags", `{}`},
+		{"POST", "/v1/quota", `{"max_bytes": 1}`},
+		{"POST", "/v1/setattr", `{"path": "/a", "mode": "rwx"}`},
+		{"POST", "/v1/restore", `{"path": "/", "cid": "nope"}`},
+		{"GET", "/v1/audit?since=yesterday", ``},
+		{"POST", "/v1/tx", `{"ops": []}`},
+		{"POST", "/v1/tx", `{"ops": [{"op": "cp", "src": "/a"}]}`},
+		{"POST", "/v1/tx", `{"ops": [{"op": "rm", "path": "/a"}], "preconditions": [{"type": "cid_equals", "path": "/a"}]}`},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: got status %d, want 400", tc.method, tc.path, w.Code)
+		}
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := &API{auth: auth.New(auth.Config{
+		Enabled: true,
+		Tokens:  []auth.Token{{Name: "viewer", Token: "read-only", Role: auth.Reader}},
+	}, nil)}
+	api.Register(router)
+	cases := []struct {
+		method, path, token string
+		want                int
+	}{
+		{"GET", "/v1/ls/", "", http.StatusUnauthorized},
+		{"GET", "/v1/ls/", "bogus", http.StatusUnauthorized},
+		{"POST", "/v1/mkdir", "read-only", http.StatusForbidden},
+		{"DELETE", "/v1/peers/QmPeer", "read-only", http.StatusForbidden},
+		{"GET", "/v1/ls/?consistency=eventual&access_token=read-only", "", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"path": "/a"}`))
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s %s: got status %d, want %d", tc.method, tc.path, w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/icetrays/icetrays/consensus"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/datastore"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func Audit(store *datastore.BadgerDB, fsm *consensus.Fsm) *consensus.Audit {
+	return consensus.NewAudit(fsm, datastore.NewAuditDB(store))
+}
+
+// origin records the client address, request id and time of the request on
+// the instructions it submits. The request id is taken from the X-Request-Id
+// header when the client sends one and is echoed in the response.
+func origin(c *gin.Context) {
+	id := c.GetHeader("X-Request-Id")
+	if id == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	c.Header("X-Request-Id", id)
+	o := &pb.Origin{Client: c.ClientIP(), RequestId: id, Time: time.Now().UnixNano()}
+	c.Set(consensus.OriginKey, o)
+	c.Request = c.Request.WithContext(consensus.WithOrigin(c.Request.Context(), o))
+	c.Next()
+}
+
+// audit lists who changed ?path= or below it, everything by default. ?since=
+// is a raft index or an RFC 3339 time, without it the latest ?limit= records
+// are returned.
+func (api *API) audit(c *gin.Context) {
+	q := datastore.AuditQuery{Path: c.Query("path")}
+	if since := c.Query("since"); since != "" {
+		if index, err := strconv.ParseUint(since, 10, 64); err == nil {
+			q.Since = index
+		} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+			q.After = t
+		} else {
+			abort(c, invalid(errors.New("since must be a raft index or an RFC 3339 time")))
+			return
+		}
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	q.Limit = limit
+	records, err := api.audits.Query(q)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
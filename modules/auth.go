@@ -0,0 +1,43 @@
+package modules
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/icetrays/icetrays/auth"
+	"strings"
+)
+
+// allow authenticates the request and aborts it unless the caller has role.
+// The token is read from "Authorization: Bearer", or from ?access_token= for
+// clients such as browsers opening a WebSocket that cannot set headers.
+func (api *API) allow(role auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := api.auth.Token(bearer(c))
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if err := auth.Authorize(id, role); err != nil {
+			abort(c, err)
+			return
+		}
+		c.Set(auth.Key, id)
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func bearer(c *gin.Context) string {
+	h := c.GetHeader("Authorization")
+	if strings.HasPrefix(h, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(h, "Bearer "))
+	}
+	return c.Query("access_token")
+}
+
+// identity is the caller authenticated by allow.
+func identity(c *gin.Context) auth.Identity {
+	if v, ok := c.Get(auth.Key); ok {
+		return v.(auth.Identity)
+	}
+	return auth.Identity{}
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/icetrays/icetrays/auth"
 	"github.com/icetrays/icetrays/network"
+	"github.com/icetrays/icetrays/webhook"
 	"github.com/jinzhu/configor"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -38,7 +40,26 @@ type Config = struct {
 		Peers    []string `json:"peers"`
 		LogLevel string   `default:"DEBUG" json:"log_level"`
 	} `json:"raft"`
-	Port int `json:"port"`
+	Port    int `json:"port"`
+	History struct {
+		// Keep is the number of versions kept, zero keeps them all.
+		Keep int `json:"keep" default:"10000"`
+		// MaxAge drops versions older than this many nanoseconds, zero
+		// keeps them regardless of age.
+		MaxAge int64 `json:"max_age"`
+	} `json:"history"`
+	Webhooks struct {
+		// Sender is the peer id of the node delivering webhooks, the
+		// current leader delivers them when it is empty.
+		Sender  string           `json:"sender"`
+		Targets []webhook.Target `json:"targets" default:"[]"`
+		// Retention is how many nanoseconds nodes that are not the
+		// sender keep queued deliveries the sender did not ack, in case
+		// the acks do not reach them.
+		Retention int64 `json:"retention" default:"3600000000000"`
+	} `json:"webhooks"`
+	Auth auth.Config `json:"auth"`
+	TLS  TLSConfig   `json:"tls"`
 }
 
 func InitConfig() Config {
@@ -60,6 +81,9 @@ func InitConfig() Config {
 		config.P2P.Identity.PeerID = id.Pretty()
 		fmt.Println("peer id: ", id)
 	}
+	if err := config.TLS.ensureCert(); err != nil {
+		panic(fmt.Errorf("generate TLS certificate: %s", err.Error()))
+	}
 	bs, _ := json.MarshalIndent(config, "", "\t")
 	if err := ioutil.WriteFile("config.json", bs, 0644); err != nil {
 		panic(fmt.Errorf("write config file: %s", err.Error()))
@@ -3,16 +3,23 @@ package modules
 import (
 	"context"
 	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/auth"
 	"github.com/icetrays/icetrays/consensus"
 	"github.com/icetrays/icetrays/datastore"
 	"github.com/icetrays/icetrays/network"
+	"github.com/icetrays/icetrays/pinning"
 	httpapi "github.com/ipfs/go-ipfs-http-client"
+	"github.com/ipfs/go-log/v2"
+	gostream "github.com/libp2p/go-libp2p-gostream"
 	p2praft "github.com/libp2p/go-libp2p-raft"
 	ma "github.com/multiformats/go-multiaddr"
 	"go.uber.org/fx"
+	"google.golang.org/grpc"
 	"time"
 )
 
+var logger = log.Logger("modules")
+
 func Network(lc fx.Lifecycle, cfg *network.NetConfig) (*network.Network, error) {
 	n, err := network.NewNetwork(*cfg)
 	if err != nil {
@@ -27,6 +34,44 @@ func Network(lc fx.Lifecycle, cfg *network.NetConfig) (*network.Network, error)
 	return n, nil
 }
 
+func GrpcServer(lc fx.Lifecycle, n *network.Network, a *auth.Authenticator) (*grpc.Server, error) {
+	listener, err := gostream.Listen(n.Host(), network.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(a.UnaryInterceptor()),
+		grpc.StreamInterceptor(a.StreamInterceptor()),
+	)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go s.Serve(listener)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			s.Stop()
+			return nil
+		},
+	})
+	return s, nil
+}
+
+// Authenticator checks the callers of both APIs against js.Auth, the members
+// of the raft cluster are always admins of the gRPC services.
+func Authenticator(js Config, r *raft.Raft) *auth.Authenticator {
+	return auth.New(js.Auth, func() []string {
+		f := r.GetConfiguration()
+		if err := f.Error(); err != nil {
+			return nil
+		}
+		var ids []string
+		for _, s := range f.Configuration().Servers {
+			ids = append(ids, string(s.ID))
+		}
+		return ids
+	})
+}
+
 func RaftConfig(js Config) *raft.Config {
 	cfg := raft.DefaultConfig()
 	cfg.SnapshotThreshold = 100
@@ -69,7 +114,11 @@ func Transport(n *network.Network) (raft.Transport, error) {
 	return p2praft.NewLibp2pTransport(n.Host(), time.Minute*2)
 }
 
-func Raft(lc fx.Lifecycle, conf *raft.Config, fsm *consensus.Fsm, snaps raft.SnapshotStore, trans raft.Transport, badger *datastore.BadgerDB, js Config) (*raft.Raft, error) {
+func LogStore(badger *datastore.BadgerDB) raft.LogStore {
+	return datastore.NewLogDB(badger)
+}
+
+func Raft(lc fx.Lifecycle, conf *raft.Config, fsm *consensus.Fsm, snaps raft.SnapshotStore, trans raft.Transport, logs raft.LogStore, badger *datastore.BadgerDB, js Config) (*raft.Raft, error) {
 	servers := make([]raft.Server, len(js.Raft.Peers))
 	for i := 0; i < len(js.Raft.Peers); i++ {
 		servers[i] = raft.Server{
@@ -78,11 +127,14 @@ func Raft(lc fx.Lifecycle, conf *raft.Config, fsm *consensus.Fsm, snaps raft.Sna
 			Address:  raft.ServerAddress(js.Raft.Peers[i]),
 		}
 	}
-	r, err := raft.NewRaft(conf, fsm, datastore.NewLogDB(badger), datastore.NewStableDB(badger), snaps, trans)
+	r, err := raft.NewRaft(conf, fsm, logs, datastore.NewStableDB(badger), snaps, trans)
 	if err != nil {
 		return nil, err
 	}
-	_ = r.BootstrapCluster(raft.Configuration{Servers: servers})
+	// nodes started without peers wait to be added by the leader
+	if len(servers) > 0 {
+		_ = r.BootstrapCluster(raft.Configuration{Servers: servers})
+	}
 	lc.Append(fx.Hook{
 		OnStart: nil,
 		OnStop: func(ctx context.Context) error {
@@ -92,7 +144,7 @@ func Raft(lc fx.Lifecycle, conf *raft.Config, fsm *consensus.Fsm, snaps raft.Sna
 	return r, nil
 }
 
-func Node(lc fx.Lifecycle, r *raft.Raft, fsm *consensus.Fsm, js Config, net *network.Network, ipfs *httpapi.HttpApi) (*consensus.Node, error) {
+func Node(lc fx.Lifecycle, r *raft.Raft, fsm *consensus.Fsm, js Config, net *network.Network, ipfs *httpapi.HttpApi, server *grpc.Server) (*consensus.Node, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	lc.Append(fx.Hook{
 		OnStart: nil,
@@ -101,7 +153,64 @@ func Node(lc fx.Lifecycle, r *raft.Raft, fsm *consensus.Fsm, js Config, net *net
 			return nil
 		},
 	})
-	return consensus.NewNode(ctx, r, fsm, js.P2P.Identity.PeerID, net, ipfs)
+	return consensus.NewNode(ctx, r, fsm, js.P2P.Identity.PeerID, net, ipfs, server)
+}
+
+func Pinner(lc fx.Lifecycle, api *httpapi.HttpApi, store *datastore.BadgerDB, fsm *consensus.Fsm, history *consensus.History, server *grpc.Server) (*pinning.Pinner, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p, err := pinning.NewPinner(ctx, api, datastore.NewPinDB(store))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	pinning.RegisterPinTrackerServer(server, pinning.NewPinStatusServer(p))
+	// tagged and retained history roots stay pinned as a whole
+	fsm.OnApplied(func(applied consensus.Applied) {
+		p.Notify(applied.Next, fsm.State.TaggedRoots()...)
+	})
+	history.OnRetained(func(root string, retained bool) {
+		if retained {
+			p.Retain(root)
+		} else {
+			p.Release(root)
+		}
+	})
+	p.Notify(fsm.State.MustGetRoot(), fsm.State.TaggedRoots()...)
+	lc.Append(fx.Hook{
+		OnStart: nil,
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return p, nil
+}
+
+func Feed(fsm *consensus.Fsm, logs raft.LogStore, server *grpc.Server) *consensus.Feed {
+	feed := consensus.NewFeed(fsm, logs)
+	consensus.RegisterChangeFeedServer(server, consensus.NewFeedServer(feed))
+	return feed
+}
+
+func History(lc fx.Lifecycle, js Config, store *datastore.BadgerDB, fsm *consensus.Fsm) (*consensus.History, error) {
+	h, err := consensus.NewHistory(fsm, datastore.NewHistoryDB(store))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go h.Retain(ctx, js.History.Keep, time.Duration(js.History.MaxAge))
+	lc.Append(fx.Hook{
+		OnStart: nil,
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return h, nil
+}
+
+func PinTracker(p *pinning.Pinner, node *consensus.Node, net *network.Network) *pinning.Tracker {
+	return pinning.NewTracker(p, node, net)
 }
 
 //type Clients struct {
@@ -0,0 +1,87 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/icetrays/icetrays/consensus"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// events streams the change feed as Server-Sent Events, or as JSON messages
+// over a WebSocket when the request asks for an upgrade. ?from= or the
+// Last-Event-ID header resume the feed from a raft index.
+func (api *API) events(c *gin.Context) {
+	from, err := eventsFrom(c)
+	if err != nil {
+		abort(c, invalid(err))
+		return
+	}
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	events, err := api.feed.Subscribe(ctx, from)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		go func() {
+			// a hijacked connection is not cancelled on disconnect, reading
+			// notices it and handles the peer's close frame.
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		for e := range events {
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+		_ = conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, consensus.ErrFeedClosed.Error()))
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Stream(func(w io.Writer) bool {
+		e, ok := <-events
+		if !ok {
+			return false
+		}
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatUint(e.Index, 10),
+			Event: "change",
+			Data:  e,
+		})
+		return true
+	})
+}
+
+func eventsFrom(c *gin.Context) (uint64, error) {
+	if from := c.Query("from"); from != "" {
+		return strconv.ParseUint(from, 10, 64)
+	}
+	if last := c.GetHeader("Last-Event-ID"); last != "" {
+		id, err := strconv.ParseUint(last, 10, 64)
+		if err != nil {
+			return 0, errors.New("invalid Last-Event-ID")
+		}
+		return id + 1, nil
+	}
+	return 0, nil
+}
@@ -1,69 +1,124 @@
 package modules
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/icetrays/icetrays/auth"
 	"github.com/icetrays/icetrays/consensus"
 	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/pinning"
+	"go.uber.org/fx"
+	"net"
+	"net/http"
 )
 
+// Op is the body of the deprecated POST /fs endpoint, use the /v1 routes instead.
+// A non empty Root makes the operation fail unless it is the current root.
 type Op struct {
 	Op     string   `json:"op"`
 	Params []string `json:"params"`
 	Root   string   `json:"root"`
 }
 
-func Server2(node *consensus.Node, config Config) {
-	router := gin.Default()
+var legacyOps = map[string]pb.Instruction_Code{
+	"cp":    pb.Instruction_CP,
+	"mv":    pb.Instruction_MV,
+	"rm":    pb.Instruction_RM,
+	"mkdir": pb.Instruction_MKDIR,
+}
 
-	// Query string parameters are parsed using the existing underlying request object.
-	// The request responds to a url matching:  /welcome?firstname=Jane&lastname=Doe
-	router.POST("/fs", func(c *gin.Context) {
-		d, err := c.GetRawData()
-		if err != nil {
-			return
-		}
-		op := &Op{}
-		err = json.Unmarshal(d, op)
+func Server2(lc fx.Lifecycle, node *consensus.Node, tracker *pinning.Tracker, feed *consensus.Feed, history *consensus.History, audits *consensus.Audit, a *auth.Authenticator, config Config) error {
+	router := gin.Default()
+	api := &API{node: node, tracker: tracker, feed: feed, history: history, auth: a, audits: audits}
+	api.Register(router)
+	// the unversioned routes predate /v1 and are kept for existing clients
+	router.POST("/fs", deprecated, origin, api.allow(auth.Reader), api.legacy)
+	router.GET("/peers", deprecated, api.allow(auth.Reader), api.peers)
+	router.POST("/peers/:id/voter", deprecated, api.allow(auth.Admin), api.membership("add_voter", node.AddVoter))
+	router.POST("/peers/:id/nonvoter", deprecated, api.allow(auth.Admin), api.membership("add_nonvoter", node.AddNonVoter))
+	router.POST("/peers/:id/demote", deprecated, api.allow(auth.Admin), api.membership("demote_voter", node.DemoteVoter))
+	router.DELETE("/peers/:id", deprecated, api.allow(auth.Admin), api.membership("remove_peer", node.RemovePeer))
+	router.GET("/pins", deprecated, api.allow(auth.Reader), api.pins)
+	router.GET("/pins/:cid", deprecated, api.allow(auth.Reader), api.pins)
+	router.GET("/metrics", api.allow(auth.Reader), api.metrics)
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", config.Port), Handler: router}
+	if config.TLS.Enabled {
+		tlsConfig, err := config.TLS.serverConfig()
 		if err != nil {
-			c.JSON(200, err.Error())
+			return err
 		}
-		switch op.Op {
-		case "ls":
-			n, _ := node.Ls(c, op.Params[0])
-			c.JSON(200, n)
-		case "cp":
-			err := node.Op(c, pb.Instruction_CP, op.Params[0], op.Params[1])
-			if err != nil {
-				c.JSON(200, err.Error())
-			} else {
-				c.JSON(200, "success")
-			}
-		case "mv":
-			err := node.Op(c, pb.Instruction_MV, op.Params[0], op.Params[1])
-			if err != nil {
-				c.JSON(200, err.Error())
-			} else {
-				c.JSON(200, "success")
-			}
-		case "rm":
-			err := node.Op(c, pb.Instruction_RM, op.Params[0])
-			if err != nil {
-				c.JSON(200, err.Error())
-			} else {
-				c.JSON(200, "success")
-			}
-		case "mkdir":
-			err := node.Op(c, pb.Instruction_MKDIR, op.Params[0])
+		srv.TLSConfig = tlsConfig
+	}
+	lc.Append(fx.Hook{
+		// bind before returning so a taken port fails the start
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", srv.Addr)
 			if err != nil {
-				c.JSON(200, err.Error())
-			} else {
-				c.JSON(200, "success")
+				return err
 			}
-		default:
-			c.JSON(200, "???")
-		}
+			go func() {
+				var err error
+				if srv.TLSConfig != nil {
+					err = srv.ServeTLS(listener, "", "")
+				} else {
+					err = srv.Serve(listener)
+				}
+				if err != nil && err != http.ErrServerClosed {
+					logger.Errorf("http server: %s", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
 	})
-	go router.Run(fmt.Sprintf(":%d", config.Port))
+	return nil
+}
+
+// deprecated points clients of the unversioned routes to /v1.
+func deprecated(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</v1>; rel="successor-version"`)
+}
+
+func (api *API) legacy(c *gin.Context) {
+	op := &Op{}
+	if err := c.ShouldBindJSON(op); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	if op.Op == "ls" {
+		if len(op.Params) != 1 {
+			c.JSON(http.StatusBadRequest, "ls takes exactly one param")
+			return
+		}
+		n, err := api.node.Ls(c, op.Params[0], consensus.Stale)
+		if err != nil {
+			c.JSON(httpStatus(consensus.ErrorCode(err)), err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, n)
+		return
+	}
+	code, ok := legacyOps[op.Op]
+	if !ok {
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("unknown op %q", op.Op))
+		return
+	}
+	if err := auth.Authorize(identity(c), auth.Writer); err != nil {
+		c.JSON(httpStatus(consensus.ErrorCode(err)), err.Error())
+		return
+	}
+	preconditions, err := Expect{Root: op.Root}.preconditions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := api.node.OpIf(c, preconditions, code, op.Params...); err != nil {
+		c.JSON(httpStatus(consensus.ErrorCode(err)), err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, "success")
 }
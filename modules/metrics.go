@@ -0,0 +1,65 @@
+package modules
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hashicorp/raft"
+	"github.com/icetrays/icetrays/datastore"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"strconv"
+)
+
+// Metrics exports the raft state and the size of the datastore next to the
+// metrics the other packages record as they run. The default registry adds
+// the Go runtime and process collectors.
+func Metrics(r *raft.Raft, store *datastore.BadgerDB) {
+	gauge := func(name, help string, fn func() float64) {
+		prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn))
+	}
+	stat := func(key string) func() float64 {
+		return func() float64 {
+			v, _ := strconv.ParseFloat(r.Stats()[key], 64)
+			return v
+		}
+	}
+	gauge("icetrays_raft_term", "Current raft term.", stat("term"))
+	gauge("icetrays_raft_commit_index", "Index of the latest committed log entry.", stat("commit_index"))
+	gauge("icetrays_raft_applied_index", "Index of the latest log entry applied to the fsm.", stat("applied_index"))
+	gauge("icetrays_raft_last_log_index", "Index of the latest log entry stored.", stat("last_log_index"))
+	gauge("icetrays_raft_state", "Raft state: 0 follower, 1 candidate, 2 leader, 3 shutdown.", func() float64 {
+		return float64(r.State())
+	})
+	gauge("icetrays_raft_leader", "1 if this node is the leader.", func() float64 {
+		if r.State() == raft.Leader {
+			return 1
+		}
+		return 0
+	})
+	gauge("icetrays_raft_has_leader", "1 if the cluster has a known leader.", func() float64 {
+		if r.Leader() != "" {
+			return 1
+		}
+		return 0
+	})
+	gauge("icetrays_raft_peers", "Servers in the raft configuration.", func() float64 {
+		f := r.GetConfiguration()
+		if f.Error() != nil {
+			return 0
+		}
+		return float64(len(f.Configuration().Servers))
+	})
+	gauge("icetrays_badger_lsm_bytes", "Size of the badger LSM tree.", func() float64 {
+		lsm, _ := store.Size()
+		return float64(lsm)
+	})
+	gauge("icetrays_badger_vlog_bytes", "Size of the badger value log.", func() float64 {
+		_, vlog := store.Size()
+		return float64(vlog)
+	})
+}
+
+var metricsHandler = promhttp.Handler()
+
+func (api *API) metrics(c *gin.Context) {
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,115 @@
+package modules
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TLSConfig serves the HTTP API over HTTPS with the certificate in CertFile
+// and KeyFile. With ClientCAFile clients must present a certificate signed
+// by one of its CAs.
+type TLSConfig struct {
+	Enabled  bool   `json:"enabled"`
+	CertFile string `json:"cert_file" default:"tls/cert.pem"`
+	KeyFile  string `json:"key_file" default:"tls/key.pem"`
+	// SelfSigned generates a self-signed certificate for Hosts on first
+	// start when CertFile does not exist.
+	SelfSigned   bool     `json:"self_signed" default:"true"`
+	Hosts        []string `json:"hosts" default:"[\"localhost\",\"127.0.0.1\"]"`
+	ClientCAFile string   `json:"client_ca_file"`
+}
+
+const selfSignedValidity = time.Hour * 24 * 365 * 10
+
+// ensureCert generates the self-signed certificate of c if it is enabled and
+// missing.
+func (c TLSConfig) ensureCert() error {
+	if !c.Enabled || !c.SelfSigned {
+		return nil
+	}
+	if _, err := os.Stat(c.CertFile); err == nil {
+		return nil
+	}
+	fmt.Println("TLS certificate not found, generating a self-signed one...")
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	tmpl := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"icetrays"}, CommonName: "icetrays"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range c.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &priv.PublicKey, priv)
+	if err != nil {
+		return err
+	}
+	key, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(c.KeyFile, "EC PRIVATE KEY", key, 0600); err != nil {
+		return err
+	}
+	return writePEM(c.CertFile, "CERTIFICATE", der, 0644)
+}
+
+func writePEM(path, kind string, der []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), perm)
+}
+
+// serverConfig loads the certificate and the client CAs of c.
+func (c TLSConfig) serverConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.ClientCAFile != "" {
+		bs, err := ioutil.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CAs: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bs) {
+			return nil, fmt.Errorf("no certificate found in %s", c.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelfSignedTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	c := TLSConfig{
+		Enabled:    true,
+		CertFile:   filepath.Join(dir, "tls", "cert.pem"),
+		KeyFile:    filepath.Join(dir, "tls", "key.pem"),
+		SelfSigned: true,
+		Hosts:      []string{"127.0.0.1"},
+	}
+	if err := c.ensureCert(); err != nil {
+		t.Fatal(err)
+	}
+	first, err := ioutil.ReadFile(c.CertFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an existing certificate is kept
+	if err := c.ensureCert(); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := ioutil.ReadFile(c.CertFile); string(again) != string(first) {
+		t.Fatal("certificate regenerated")
+	}
+
+	// a client CA makes the server require client certificates
+	c.ClientCAFile = c.CertFile
+	config, err := c.serverConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("got client auth %v", config.ClientAuth)
+	}
+	config.ClientAuth = tls.NoClientCert
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = config
+	srv.StartTLS()
+	defer srv.Close()
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(first)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	res, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
@@ -0,0 +1,58 @@
+package modules
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus"
+	"github.com/icetrays/icetrays/datastore"
+	"github.com/icetrays/icetrays/network"
+	"github.com/icetrays/icetrays/webhook"
+	"github.com/icetrays/icetrays/webhook/pb"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"time"
+)
+
+const ackTimeout = time.Second * 5
+
+func Webhooks(lc fx.Lifecycle, js Config, store *datastore.BadgerDB, fsm *consensus.Fsm, node *consensus.Node, net *network.Network, server *grpc.Server) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sender := node.IsLeader
+	if id := js.Webhooks.Sender; id != "" {
+		sender = func() bool { return node.ID == id }
+	}
+	ack := func(index uint64) {
+		peers, err := node.Peers()
+		if err != nil {
+			logger.Warnf("ack webhook deliveries: %s", err)
+			return
+		}
+		for _, peer := range peers {
+			if peer.ID == node.ID {
+				continue
+			}
+			go func(id string) {
+				actx, cancel := context.WithTimeout(ctx, ackTimeout)
+				defer cancel()
+				conn, err := net.Connect(actx, id)
+				if err == nil {
+					_, err = webhook.NewDeliveriesClient(conn).Delivered(actx, &pb.DeliveredRequest{Index: index})
+				}
+				if err != nil {
+					logger.Debugf("ack webhook deliveries up to %d to %s: %s", index, id, err)
+				}
+			}(peer.ID)
+		}
+	}
+	d := webhook.NewDispatcher(ctx, js.Webhooks.Targets, datastore.NewWebhookDB(store), sender, ack, time.Duration(js.Webhooks.Retention))
+	webhook.RegisterDeliveriesServer(server, webhook.NewAckServer(d))
+	fsm.OnApplied(func(applied consensus.Applied) {
+		d.Notify(consensus.NewEvent(applied))
+	})
+	lc.Append(fx.Hook{
+		OnStart: nil,
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
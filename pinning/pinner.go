@@ -0,0 +1,372 @@
+package pinning
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/datastore"
+	"github.com/ipfs/go-cid"
+	httpapi "github.com/ipfs/go-ipfs-http-client"
+	"github.com/ipfs/go-log/v2"
+	"github.com/ipfs/interface-go-ipfs-core/options"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ModeDirect    = "direct"
+	ModeRecursive = "recursive"
+)
+
+const (
+	StateQueued  = "queued"
+	StatePinning = "pinning"
+	StatePinned  = "pinned"
+	StateFailed  = "failed"
+)
+
+const retryInterval = time.Second * 10
+
+var logger = log.Logger("pinner")
+
+// Pinner keeps every node reachable from the latest file tree root pinned on
+// the local IPFS node. Directories are pinned directly and walked, files are
+// pinned recursively, and everything it pinned is recorded in the datastore
+// so that CIDs dropped from the tree can be unpinned after a restart. The
+// extra roots passed to Notify and the roots held with Retain are pinned
+// recursively as a whole until they are dropped or released.
+type Pinner struct {
+	api    *httpapi.HttpApi
+	store  *datastore.PinDB
+	roots  chan pinSet
+	wake   chan struct{}
+	ctx    context.Context
+	mtx    sync.RWMutex
+	status map[string]PinInfo
+	// retains collects the Retain and Release calls until run picks them up.
+	retainMtx sync.Mutex
+	retains   map[string]bool
+
+	// owned by run
+	tree     *tree
+	root     cid.Cid
+	extra    map[string]bool
+	retained map[string]bool
+	pending  map[string]bool
+	pinned   map[string]string
+}
+
+type PinInfo struct {
+	Cid     string    `json:"cid"`
+	State   string    `json:"state"`
+	Mode    string    `json:"mode"`
+	Error   string    `json:"error,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// Status returns the pin state of c, or of every CID in the file tree when c
+// is empty.
+func (p *Pinner) Status(c string) []PinInfo {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	if c != "" {
+		if info, ok := p.status[c]; ok {
+			return []PinInfo{info}
+		}
+		return []PinInfo{}
+	}
+	infos := make([]PinInfo, 0, len(p.status))
+	for _, info := range p.status {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Cid < infos[j].Cid
+	})
+	return infos
+}
+
+func (p *Pinner) setState(c, mode, state string, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	info := PinInfo{
+		Cid:     c,
+		State:   state,
+		Mode:    mode,
+		Updated: time.Now(),
+	}
+	if err != nil {
+		info.Error = err.Error()
+	}
+	p.status[c] = info
+}
+
+type pinSet struct {
+	root   string
+	tagged []string
+}
+
+func (p *Pinner) Notify(root string, tagged ...string) {
+	set := pinSet{root: root, tagged: tagged}
+	for {
+		select {
+		case p.roots <- set:
+			return
+		default:
+		}
+		// drop the pending root, only the latest one matters
+		select {
+		case <-p.roots:
+		default:
+		}
+	}
+}
+
+// Retain keeps root pinned recursively until it is released.
+func (p *Pinner) Retain(root string) {
+	p.setRetained(root, true)
+}
+
+// Release drops a root kept with Retain.
+func (p *Pinner) Release(root string) {
+	p.setRetained(root, false)
+}
+
+func (p *Pinner) setRetained(root string, retained bool) {
+	p.retainMtx.Lock()
+	p.retains[root] = retained
+	p.retainMtx.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pinner) run() {
+	var set pinSet
+	retry := time.NewTimer(retryInterval)
+	retry.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			retry.Stop()
+			return
+		case set = <-p.roots:
+		case <-p.wake:
+		case <-retry.C:
+		}
+		// nothing to pin before the first root arrives
+		if set.root == "" {
+			continue
+		}
+		if err := p.Reconcile(p.ctx, set.root, set.tagged...); err != nil {
+			logger.Warnf("reconcile pins for %s: %s", set.root, err)
+			retry.Reset(retryInterval)
+		}
+	}
+}
+
+// Reconcile pins the nodes of the tree at root and the extra roots, and
+// unpins what is no longer wanted. Only the nodes that changed since the last
+// call are looked at, the first call after a start walks the whole tree and
+// compares it with the pins recorded before. It must not be called
+// concurrently, run is its only caller.
+func (p *Pinner) Reconcile(ctx context.Context, root string, extra ...string) error {
+	c, err := cid.Decode(root)
+	if err != nil {
+		return err
+	}
+	extras := make(map[string]bool, len(extra))
+	for _, e := range extra {
+		ec, err := cid.Decode(e)
+		if err != nil {
+			return err
+		}
+		extras[ec.String()] = true
+	}
+	if err := p.advance(ctx, c); err != nil {
+		return err
+	}
+	for e := range extras {
+		if !p.extra[e] {
+			p.pending[e] = true
+		}
+	}
+	for e := range p.extra {
+		if !extras[e] {
+			p.pending[e] = true
+		}
+	}
+	p.extra = extras
+	p.retainMtx.Lock()
+	retains := p.retains
+	p.retains = make(map[string]bool)
+	p.retainMtx.Unlock()
+	for r, retained := range retains {
+		rc, err := cid.Decode(r)
+		if err != nil {
+			logger.Warnf("retain %s: %s", r, err)
+			continue
+		}
+		if retained {
+			p.retained[rc.String()] = true
+		} else {
+			delete(p.retained, rc.String())
+		}
+		p.pending[rc.String()] = true
+	}
+	return p.sync(ctx)
+}
+
+// advance moves the tree to root and marks the nodes that entered or left it
+// as pending.
+func (p *Pinner) advance(ctx context.Context, root cid.Cid) error {
+	if p.tree == nil {
+		t := newTree(p.api.Dag().Get)
+		if err := t.add(ctx, root); err != nil {
+			return err
+		}
+		p.tree, p.root = t, root
+		for c := range p.pinned {
+			p.pending[c] = true
+		}
+	} else if !root.Equals(p.root) {
+		err := p.tree.add(ctx, root)
+		if err == nil {
+			err = p.tree.remove(ctx, p.root)
+		}
+		if err != nil {
+			// the counts are off after a partial update, start over
+			p.tree = nil
+			return err
+		}
+		p.root = root
+	}
+	for c := range p.tree.changed {
+		p.pending[c] = true
+	}
+	p.tree.changed = make(map[string]bool)
+	return nil
+}
+
+func (p *Pinner) want(c string) string {
+	if p.extra[c] || p.retained[c] {
+		return ModeRecursive
+	}
+	return p.tree.mode(c)
+}
+
+// sync pins and unpins the pending nodes. Nothing is unpinned while a pin
+// failed, the pending nodes are retried on the next call.
+func (p *Pinner) sync(ctx context.Context) error {
+	var queued, stale []string
+	for c := range p.pending {
+		mode := p.want(c)
+		had, ok := p.pinned[c]
+		switch {
+		case mode == "":
+			p.mtx.Lock()
+			delete(p.status, c)
+			p.mtx.Unlock()
+			if ok {
+				stale = append(stale, c)
+			} else {
+				delete(p.pending, c)
+			}
+		case had == mode:
+			p.setState(c, mode, StatePinned, nil)
+			delete(p.pending, c)
+		default:
+			p.setState(c, mode, StateQueued, nil)
+			queued = append(queued, c)
+		}
+	}
+	var failed error
+	for _, c := range queued {
+		mode := p.want(c)
+		// a recursive pin can not be turned into a direct one in place
+		if had := p.pinned[c]; had == ModeRecursive {
+			if err := p.unpin(ctx, c, had); err != nil {
+				p.setState(c, mode, StateFailed, err)
+				failed = err
+				continue
+			}
+		}
+		p.setState(c, mode, StatePinning, nil)
+		if err := p.pin(ctx, c, mode); err != nil {
+			p.setState(c, mode, StateFailed, err)
+			failed = err
+			continue
+		}
+		p.setState(c, mode, StatePinned, nil)
+		delete(p.pending, c)
+	}
+	if failed != nil {
+		return failed
+	}
+	for _, c := range stale {
+		if err := p.unpin(ctx, c, p.pinned[c]); err != nil {
+			return err
+		}
+		delete(p.pending, c)
+	}
+	return nil
+}
+
+func (p *Pinner) pin(ctx context.Context, c string, mode string) error {
+	id, err := cid.Decode(c)
+	if err != nil {
+		return err
+	}
+	err = p.api.Pin().Add(ctx, path.IpfsPath(id), options.Pin.Recursive(mode == ModeRecursive))
+	if err != nil {
+		return err
+	}
+	if err := p.store.AddPin(c, mode); err != nil {
+		return err
+	}
+	p.pinned[c] = mode
+	return nil
+}
+
+func (p *Pinner) unpin(ctx context.Context, c string, mode string) error {
+	id, err := cid.Decode(c)
+	if err != nil {
+		return err
+	}
+	err = p.api.Pin().Rm(ctx, path.IpfsPath(id), options.Pin.RmRecursive(mode == ModeRecursive))
+	// already unpinned before the record was removed
+	if err != nil && !strings.Contains(err.Error(), "not pinned") {
+		return err
+	}
+	if err := p.store.RemovePin(c); err != nil {
+		return err
+	}
+	delete(p.pinned, c)
+	return nil
+}
+
+func NewPinner(ctx context.Context, api *httpapi.HttpApi, store *datastore.PinDB) (*Pinner, error) {
+	p := &Pinner{
+		api:      api,
+		store:    store,
+		roots:    make(chan pinSet, 1),
+		wake:     make(chan struct{}, 1),
+		ctx:      ctx,
+		status:   make(map[string]PinInfo),
+		retains:  make(map[string]bool),
+		extra:    make(map[string]bool),
+		retained: make(map[string]bool),
+		pending:  make(map[string]bool),
+	}
+	pins, err := store.Pins()
+	if err != nil {
+		return nil, err
+	}
+	p.pinned = pins
+	for c, mode := range pins {
+		p.setState(c, mode, StatePinned, nil)
+	}
+	go p.run()
+	return p, nil
+}
@@ -0,0 +1,137 @@
+package pinning
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/consensus"
+	"github.com/icetrays/icetrays/network"
+	"github.com/icetrays/icetrays/pinning/pb"
+	"sort"
+	"sync"
+	"time"
+)
+
+const statusTimeout = time.Second * 5
+
+type GlobalPinInfo struct {
+	Cid   string             `json:"cid"`
+	Peers map[string]PinInfo `json:"peers"`
+}
+
+type PinReport struct {
+	Pins   []GlobalPinInfo   `json:"pins"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Tracker collects the pin state reported by every raft peer.
+type Tracker struct {
+	pinner  *Pinner
+	node    *consensus.Node
+	network *network.Network
+}
+
+func (t *Tracker) Status(ctx context.Context, c string) (*PinReport, error) {
+	peers, err := t.node.Peers()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([][]PinInfo, len(peers))
+	errs := make([]error, len(peers))
+	wg := sync.WaitGroup{}
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			infos[i], errs[i] = t.peerStatus(ctx, id, c)
+		}(i, peer.ID)
+	}
+	wg.Wait()
+
+	report := &PinReport{
+		Pins:   []GlobalPinInfo{},
+		Errors: map[string]string{},
+	}
+	byCid := make(map[string]GlobalPinInfo)
+	for i, peer := range peers {
+		if errs[i] != nil {
+			report.Errors[peer.ID] = errs[i].Error()
+			continue
+		}
+		for _, info := range infos[i] {
+			global, ok := byCid[info.Cid]
+			if !ok {
+				global = GlobalPinInfo{Cid: info.Cid, Peers: map[string]PinInfo{}}
+				byCid[info.Cid] = global
+			}
+			global.Peers[peer.ID] = info
+		}
+	}
+	for _, global := range byCid {
+		report.Pins = append(report.Pins, global)
+	}
+	sort.Slice(report.Pins, func(i, j int) bool {
+		return report.Pins[i].Cid < report.Pins[j].Cid
+	})
+	return report, nil
+}
+
+func (t *Tracker) peerStatus(ctx context.Context, id string, c string) ([]PinInfo, error) {
+	if id == t.node.ID {
+		return t.pinner.Status(c), nil
+	}
+	conn, err := t.network.Connect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+	res, err := NewPinTrackerClient(conn).Status(cctx, &pb.PinStatusRequest{Cid: c})
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]PinInfo, len(res.GetPins()))
+	for i, info := range res.GetPins() {
+		infos[i] = PinInfo{
+			Cid:     info.GetCid(),
+			State:   info.GetState(),
+			Mode:    info.GetMode(),
+			Error:   info.GetError(),
+			Updated: time.Unix(0, info.GetUpdated()),
+		}
+	}
+	return infos, nil
+}
+
+func NewTracker(pinner *Pinner, node *consensus.Node, net *network.Network) *Tracker {
+	return &Tracker{
+		pinner:  pinner,
+		node:    node,
+		network: net,
+	}
+}
+
+type PinStatusServer struct {
+	pinner *Pinner
+}
+
+func (s PinStatusServer) Status(ctx context.Context, req *pb.PinStatusRequest) (*pb.PinInfos, error) {
+	infos := s.pinner.Status(req.GetCid())
+	res := &pb.PinInfos{Pins: make([]*pb.PinInfo, len(infos))}
+	for i, info := range infos {
+		res.Pins[i] = &pb.PinInfo{
+			Cid:     info.Cid,
+			State:   info.State,
+			Mode:    info.Mode,
+			Error:   info.Error,
+			Updated: info.Updated.UnixNano(),
+		}
+	}
+	return res, nil
+}
+
+func (s PinStatusServer) mustEmbedUnimplementedPinTrackerServer() {
+
+}
+
+func NewPinStatusServer(pinner *Pinner) PinStatusServer {
+	return PinStatusServer{pinner: pinner}
+}
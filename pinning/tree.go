@@ -0,0 +1,109 @@
+package pinning
+
+import (
+	"context"
+	"github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-unixfs"
+)
+
+// tree counts the references to the nodes reachable from a root. A node is
+// only walked when its count leaves or drops to zero, so moving to a new root
+// fetches the directories that changed and leaves shared subtrees alone.
+type tree struct {
+	get   func(ctx context.Context, c cid.Cid) (format.Node, error)
+	refs  map[string]int
+	modes map[string]string
+	// changed collects the nodes that entered or left the tree.
+	changed map[string]bool
+}
+
+func newTree(get func(ctx context.Context, c cid.Cid) (format.Node, error)) *tree {
+	return &tree{
+		get:     get,
+		refs:    make(map[string]int),
+		modes:   make(map[string]string),
+		changed: make(map[string]bool),
+	}
+}
+
+// mode returns how c has to be pinned, or "" when it is not in the tree.
+func (t *tree) mode(c string) string {
+	return t.modes[c]
+}
+
+func (t *tree) add(ctx context.Context, c cid.Cid) error {
+	k := c.String()
+	t.refs[k]++
+	if t.refs[k] > 1 {
+		return nil
+	}
+	t.changed[k] = true
+	mode, links, err := t.node(ctx, c)
+	if err != nil {
+		return err
+	}
+	t.modes[k] = mode
+	for _, link := range links {
+		if err := t.add(ctx, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tree) remove(ctx context.Context, c cid.Cid) error {
+	k := c.String()
+	t.refs[k]--
+	if t.refs[k] > 0 {
+		return nil
+	}
+	t.changed[k] = true
+	mode := t.modes[k]
+	delete(t.refs, k)
+	delete(t.modes, k)
+	if mode != ModeDirect {
+		return nil
+	}
+	_, links, err := t.node(ctx, c)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		if err := t.remove(ctx, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// node tells how c is pinned: directories directly with their links walked,
+// everything else recursively.
+func (t *tree) node(ctx context.Context, c cid.Cid) (string, []cid.Cid, error) {
+	if c.Type() != cid.DagProtobuf {
+		return ModeRecursive, nil, nil
+	}
+	nd, err := t.get(ctx, c)
+	if err != nil {
+		return "", nil, err
+	}
+	pn, ok := nd.(*merkledag.ProtoNode)
+	if !ok {
+		return ModeRecursive, nil, nil
+	}
+	fsn, err := unixfs.FSNodeFromBytes(pn.Data())
+	if err != nil {
+		return "", nil, err
+	}
+	switch fsn.Type() {
+	case unixfs.TDirectory, unixfs.THAMTShard:
+		links := make([]cid.Cid, 0, len(pn.Links()))
+		for _, link := range pn.Links() {
+			links = append(links, link.Cid)
+		}
+		return ModeDirect, links, nil
+	default:
+		return ModeRecursive, nil, nil
+	}
+}
@@ -0,0 +1,81 @@
+package pinning
+
+import (
+	"context"
+	"github.com/ipfs/go-cid"
+	format "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+	"github.com/ipfs/go-unixfs"
+	"testing"
+)
+
+func TestTreeOnlyWalksChanges(t *testing.T) {
+	ctx := context.Background()
+	dag := mdtest.Mock()
+	add := func(nd *merkledag.ProtoNode) *merkledag.ProtoNode {
+		if err := dag.Add(ctx, nd); err != nil {
+			t.Fatal(err)
+		}
+		return nd
+	}
+	file := func(content string) *merkledag.ProtoNode {
+		return add(merkledag.NodeWithData(unixfs.FilePBData([]byte(content), uint64(len(content)))))
+	}
+	dir := func(links map[string]*merkledag.ProtoNode) *merkledag.ProtoNode {
+		nd := unixfs.EmptyDirNode()
+		for name, child := range links {
+			if err := nd.AddNodeLink(name, child); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return add(nd)
+	}
+
+	a, b := file("a"), file("b")
+	shared := dir(map[string]*merkledag.ProtoNode{"a": a})
+	old := dir(map[string]*merkledag.ProtoNode{"shared": shared, "b": b, "copy": a})
+	next := dir(map[string]*merkledag.ProtoNode{"shared": shared})
+
+	var fetched []string
+	tr := newTree(func(ctx context.Context, c cid.Cid) (format.Node, error) {
+		fetched = append(fetched, c.String())
+		return dag.Get(ctx, c)
+	})
+	if err := tr.add(ctx, old.Cid()); err != nil {
+		t.Fatal(err)
+	}
+	tr.changed = make(map[string]bool)
+	fetched = nil
+	if err := tr.add(ctx, next.Cid()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.remove(ctx, old.Cid()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the unchanged subtree is neither fetched nor reported
+	for _, c := range fetched {
+		if c == shared.Cid().String() {
+			t.Fatal("fetched the unchanged subtree")
+		}
+	}
+	if tr.changed[shared.Cid().String()] || tr.changed[a.Cid().String()] {
+		t.Fatalf("unchanged nodes reported: %v", tr.changed)
+	}
+	want := map[string]string{
+		next.Cid().String():   ModeDirect,
+		shared.Cid().String(): ModeDirect,
+		a.Cid().String():      ModeRecursive,
+		old.Cid().String():    "",
+		b.Cid().String():      "",
+	}
+	for c, mode := range want {
+		if got := tr.mode(c); got != mode {
+			t.Errorf("mode of %s is %q, want %q", c, got, mode)
+		}
+	}
+	if !tr.changed[old.Cid().String()] || !tr.changed[b.Cid().String()] || !tr.changed[next.Cid().String()] {
+		t.Fatalf("changes missing: %v", tr.changed)
+	}
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+	"github.com/icetrays/icetrays/webhook/pb"
+)
+
+// AckServer receives the acks of the sender for the local dispatcher.
+type AckServer struct {
+	dispatcher *Dispatcher
+}
+
+func (s AckServer) Delivered(ctx context.Context, req *pb.DeliveredRequest) (*pb.DeliveredResponse, error) {
+	s.dispatcher.Delivered(req.GetIndex())
+	return &pb.DeliveredResponse{}, nil
+}
+
+func (s AckServer) mustEmbedUnimplementedDeliveriesServer() {
+
+}
+
+func NewAckServer(d *Dispatcher) AckServer {
+	return AckServer{dispatcher: d}
+}
@@ -0,0 +1,420 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/datastore"
+	"github.com/ipfs/go-log/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 10
+	minBackoff         = time.Second
+	maxBackoff         = time.Minute * 10
+	// senderPoll is how often a node that is not the sender checks whether
+	// it became the sender.
+	senderPoll = time.Second * 5
+	// ackInterval is how often the sender tells the other nodes up to
+	// which index it delivered.
+	ackInterval = time.Second * 5
+)
+
+var logger = log.Logger("webhook")
+
+// Target is an endpoint notified of the changes whose path starts with one
+// of Prefixes and whose op is one of Ops, empty filters match everything.
+// With a Secret every payload is signed with HMAC-SHA256 in the
+// X-Icetrays-Signature header.
+type Target struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	Prefixes    []string `json:"prefixes"`
+	Ops         []string `json:"ops"`
+	MaxAttempts int      `json:"max_attempts"`
+}
+
+func (t *Target) match(c *pb.Change) bool {
+	if len(t.Ops) > 0 && !contains(t.Ops, c.Op) {
+		return false
+	}
+	if len(t.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range t.Prefixes {
+		if strings.HasPrefix(c.Path, prefix) || strings.HasPrefix(c.Src, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+type delivery struct {
+	Target   string          `json:"target"`
+	Index    uint64          `json:"index"`
+	Body     json.RawMessage `json:"body"`
+	Attempts int             `json:"attempts"`
+	Next     time.Time       `json:"next"`
+	Queued   time.Time       `json:"queued"`
+}
+
+// Dispatcher queues an event for every target it matches and delivers the
+// queue in the background, retrying failures with exponential backoff. The
+// queue lives in the datastore so deliveries survive restarts. Every node
+// queues, but only the node for which sender returns true delivers, so that
+// a change is not posted once per node and a node that becomes the sender
+// delivers what the previous one may not have. The sender passes the index
+// up to which it is done to ack every few seconds, the other nodes hand it to
+// Delivered and drop their copies, so a new sender only posts what was queued
+// since the last ack. Receivers may still see an event twice and should
+// ignore X-Icetrays-Event indexes they already processed. Nodes that are not
+// the sender also drop deliveries queued more than retention ago, in case the
+// acks do not reach them.
+type Dispatcher struct {
+	targets   map[string]*Target
+	store     *datastore.QueueDB
+	sender    func() bool
+	ack       func(index uint64)
+	retention time.Duration
+	client    *http.Client
+	wake      chan struct{}
+	ctx       context.Context
+	// last is the index of the latest event notified, delivered the one
+	// the sender acked last.
+	last      uint64
+	delivered uint64
+}
+
+func NewDispatcher(ctx context.Context, targets []Target, store *datastore.QueueDB, sender func() bool, ack func(index uint64), retention time.Duration) *Dispatcher {
+	d := &Dispatcher{
+		targets:   make(map[string]*Target),
+		store:     store,
+		sender:    sender,
+		ack:       ack,
+		retention: retention,
+		client:    &http.Client{Timeout: time.Second * 10},
+		wake:      make(chan struct{}, 1),
+		ctx:       ctx,
+	}
+	for i := range targets {
+		t := targets[i]
+		if t.Name == "" {
+			t.Name = t.URL
+		}
+		if t.MaxAttempts <= 0 {
+			t.MaxAttempts = defaultMaxAttempts
+		}
+		d.targets[t.Name] = &t
+	}
+	go d.run()
+	return d
+}
+
+// Notify queues e for every matching target. It is called on the raft apply
+// goroutine, so it only writes to the datastore and leaves delivery to run.
+func (d *Dispatcher) Notify(e *pb.Event) {
+	// stored after the deliveries are queued, see watermark
+	defer atomic.StoreUint64(&d.last, e.Index)
+	if len(d.targets) == 0 || e.Index <= atomic.LoadUint64(&d.delivered) {
+		return
+	}
+	queued := false
+	for _, t := range d.targets {
+		var matched []*pb.Change
+		for _, c := range e.Changes {
+			if t.match(c) {
+				matched = append(matched, c)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		body, err := json.Marshal(&pb.Event{
+			Index:   e.Index,
+			Term:    e.Term,
+			Pre:     e.Pre,
+			Next:    e.Next,
+			Changes: matched,
+		})
+		if err != nil {
+			logger.Errorf("encode event %d: %s", e.Index, err)
+			continue
+		}
+		if err := d.put(&delivery{Target: t.Name, Index: e.Index, Body: body, Queued: time.Now()}); err != nil {
+			logger.Errorf("queue event %d for %s: %s", e.Index, t.Name, err)
+			continue
+		}
+		queued = true
+	}
+	if queued {
+		d.nudge()
+	}
+}
+
+func (d *Dispatcher) nudge() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var acked time.Time
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+		wait := senderPoll
+		if d.sender() {
+			wait = d.deliverDue()
+			if time.Since(acked) >= ackInterval {
+				d.acknowledge()
+				acked = time.Now()
+			}
+			if wait > ackInterval {
+				wait = ackInterval
+			}
+		} else {
+			d.dropStale()
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// deliverDue posts every queued delivery whose time has come and returns how
+// long to wait until the next one is due.
+func (d *Dispatcher) deliverDue() time.Duration {
+	var due []*delivery
+	wait := maxBackoff
+	now := time.Now()
+	err := d.store.Items(func(key, val []byte) error {
+		dl := &delivery{}
+		if err := json.Unmarshal(val, dl); err != nil {
+			logger.Errorf("drop undecodable delivery %x: %s", key, err)
+			return d.store.Delete(key)
+		}
+		if dl.Next.After(now) {
+			if w := dl.Next.Sub(now); w < wait {
+				wait = w
+			}
+			return nil
+		}
+		due = append(due, dl)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("read queue: %s", err)
+		return minBackoff
+	}
+	for _, dl := range due {
+		if d.ctx.Err() != nil {
+			break
+		}
+		t, ok := d.targets[dl.Target]
+		if !ok {
+			d.remove(dl)
+			continue
+		}
+		err := d.post(t, dl)
+		if err == nil {
+			d.remove(dl)
+			continue
+		}
+		dl.Attempts++
+		if dl.Attempts >= t.MaxAttempts {
+			logger.Errorf("give up delivering event %d to %s after %d attempts: %s", dl.Index, t.Name, dl.Attempts, err)
+			d.remove(dl)
+			continue
+		}
+		backoff := backoff(dl.Attempts)
+		logger.Warnf("deliver event %d to %s: %s, retry in %s", dl.Index, t.Name, err, backoff)
+		dl.Next = time.Now().Add(backoff)
+		if err := d.put(dl); err != nil {
+			logger.Errorf("requeue event %d for %s: %s", dl.Index, t.Name, err)
+		}
+		if backoff < wait {
+			wait = backoff
+		}
+	}
+	return wait
+}
+
+// acknowledge passes the watermark to ack, even when it did not move, so
+// that nodes which missed an earlier ack catch up.
+func (d *Dispatcher) acknowledge() {
+	if d.ack == nil {
+		return
+	}
+	mark, err := d.watermark()
+	if err != nil {
+		logger.Errorf("read queue: %s", err)
+		return
+	}
+	if mark > 0 {
+		d.ack(mark)
+	}
+}
+
+// watermark is the highest index up to which every delivery is done: just
+// below the oldest one still queued, or the latest event when the queue is
+// empty. last is read before the queue so that no event it covers can be
+// queued after the queue was read.
+func (d *Dispatcher) watermark() (uint64, error) {
+	mark := atomic.LoadUint64(&d.last)
+	err := d.store.Items(func(key, val []byte) error {
+		if len(key) < 8 {
+			return nil
+		}
+		if index := binary.BigEndian.Uint64(key); index > 0 && index <= mark {
+			mark = index - 1
+		}
+		return nil
+	})
+	return mark, err
+}
+
+// Delivered drops the queued deliveries up to and including index, which the
+// sender is done with, and keeps later notifications of them from being
+// queued.
+func (d *Dispatcher) Delivered(index uint64) {
+	for {
+		old := atomic.LoadUint64(&d.delivered)
+		if index <= old || atomic.CompareAndSwapUint64(&d.delivered, old, index) {
+			break
+		}
+	}
+	var done [][]byte
+	err := d.store.Items(func(key, val []byte) error {
+		if len(key) < 8 || binary.BigEndian.Uint64(key) <= index {
+			done = append(done, key)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("read queue: %s", err)
+		return
+	}
+	for _, key := range done {
+		if err := d.store.Delete(key); err != nil {
+			logger.Errorf("drop delivered event %x: %s", key, err)
+		}
+	}
+}
+
+// dropStale removes the deliveries queued more than retention ago, the
+// sender has had the time to deliver them.
+func (d *Dispatcher) dropStale() {
+	if d.retention <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-d.retention)
+	var stale [][]byte
+	err := d.store.Items(func(key, val []byte) error {
+		dl := &delivery{}
+		if err := json.Unmarshal(val, dl); err != nil || dl.Queued.Before(cutoff) {
+			stale = append(stale, key)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("read queue: %s", err)
+		return
+	}
+	for _, key := range stale {
+		if err := d.store.Delete(key); err != nil {
+			logger.Errorf("drop stale delivery %x: %s", key, err)
+		}
+	}
+}
+
+func (d *Dispatcher) post(t *Target, dl *delivery) error {
+	req, err := http.NewRequestWithContext(d.ctx, "POST", t.URL, bytes.NewReader(dl.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Icetrays-Event", strconv.FormatUint(dl.Index, 10))
+	req.Header.Set("X-Icetrays-Attempt", strconv.Itoa(dl.Attempts+1))
+	if t.Secret != "" {
+		req.Header.Set("X-Icetrays-Signature", Sign(t.Secret, dl.Body))
+	}
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+// Sign returns the X-Icetrays-Signature header of body, receivers compute it
+// with their copy of the secret and compare.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func backoff(attempts int) time.Duration {
+	b := minBackoff << uint(attempts-1)
+	if b <= 0 || b > maxBackoff {
+		return maxBackoff
+	}
+	return b
+}
+
+func (d *Dispatcher) put(dl *delivery) error {
+	val, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	return d.store.Put(key(dl), val)
+}
+
+func (d *Dispatcher) remove(dl *delivery) {
+	if err := d.store.Delete(key(dl)); err != nil {
+		logger.Errorf("dequeue event %d for %s: %s", dl.Index, dl.Target, err)
+	}
+}
+
+// key orders deliveries by raft index, the same entry queued again after a
+// restart overwrites its earlier delivery.
+func key(dl *delivery) []byte {
+	k := make([]byte, 8, 8+len(dl.Target))
+	binary.BigEndian.PutUint64(k, dl.Index)
+	return append(k, dl.Target...)
+}
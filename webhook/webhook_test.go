@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/icetrays/icetrays/consensus/pb"
+	"github.com/icetrays/icetrays/datastore"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := datastore.NewBadgerStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	received := make(chan *pb.Event, 1)
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("X-Icetrays-Signature") != Sign("secret", body) {
+			t.Errorf("bad signature")
+		}
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		e := &pb.Event{}
+		if err := json.Unmarshal(body, e); err != nil {
+			t.Error(err)
+		}
+		received <- e
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d := NewDispatcher(ctx, []Target{{
+		URL:      server.URL,
+		Secret:   "secret",
+		Prefixes: []string{"/photos"},
+		Ops:      []string{"cp", "mv"},
+	}}, datastore.NewWebhookDB(db), func() bool { return true }, nil, time.Hour)
+
+	d.Notify(&pb.Event{Index: 1, Changes: []*pb.Change{{Op: "cp", Path: "/docs/a"}}})
+	d.Notify(&pb.Event{Index: 2, Changes: []*pb.Change{
+		{Op: "rm", Path: "/photos/a"},
+		{Op: "mv", Src: "/photos/b", Path: "/archive/b"},
+	}})
+
+	select {
+	case e := <-received:
+		if e.Index != 2 || len(e.Changes) != 1 || e.Changes[0].Op != "mv" {
+			t.Fatalf("unexpected event: %v", e)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("event not delivered")
+	}
+	if calls != 2 {
+		t.Fatalf("expected one retry, got %d calls", calls)
+	}
+}
+
+// TestDispatcherFollower checks that a node queues while it is not the
+// sender, drops what the sender acked and delivers the rest once it becomes
+// the sender.
+func TestDispatcherFollower(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := datastore.NewBadgerStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	received := make(chan uint64, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := &pb.Event{}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, e); err != nil {
+			t.Error(err)
+		}
+		received <- e.Index
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var sender int32
+	acks := make(chan uint64, 1)
+	d := NewDispatcher(ctx, []Target{{URL: server.URL}}, datastore.NewWebhookDB(db), func() bool {
+		return atomic.LoadInt32(&sender) == 1
+	}, func(index uint64) {
+		acks <- index
+	}, time.Hour)
+	d.Notify(&pb.Event{Index: 7, Changes: []*pb.Change{{Op: "mkdir", Path: "/a"}}})
+	d.Notify(&pb.Event{Index: 8, Changes: []*pb.Change{{Op: "mkdir", Path: "/b"}}})
+	select {
+	case i := <-received:
+		t.Fatalf("event %d delivered by a node that is not the sender", i)
+	case <-time.After(time.Millisecond * 200):
+	}
+	d.Delivered(7)
+
+	atomic.StoreInt32(&sender, 1)
+	d.nudge()
+	select {
+	case i := <-received:
+		if i != 8 {
+			t.Fatalf("got event %d, want 8", i)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("queued event not delivered after becoming the sender")
+	}
+	select {
+	case i := <-acks:
+		if i != 8 {
+			t.Fatalf("acked up to %d, want 8", i)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("deliveries not acked")
+	}
+}